@@ -38,6 +38,7 @@ Visit the website https://minekube.com/gate`
 		debug      bool
 		configFile string
 		verbosity  int
+		noConsole  bool
 	)
 	app.Flags = []cli.Flag{
 		&cli.StringFlag{
@@ -62,6 +63,12 @@ Supports: yaml/yml, json, toml, hcl, ini, prop/properties/props, env/dotenv`,
 			EnvVars:     []string{"GATE_VERBOSITY"},
 			Destination: &verbosity,
 		},
+		&cli.BoolFlag{
+			Name:        "no-console",
+			Usage:       "Disable reading proxy commands from stdin",
+			Destination: &noConsole,
+			EnvVars:     []string{"GATE_NO_CONSOLE"},
+		},
 	}
 	app.Action = func(c *cli.Context) error {
 		// Init viper
@@ -94,7 +101,18 @@ Supports: yaml/yml, json, toml, hcl, ini, prop/properties/props, env/dotenv`,
 		log.Info("using config file", "config", v.ConfigFileUsed())
 
 		// Start Gate
-		if err = gate.Start(c.Context, gate.WithConfig(*cfg)); err != nil {
+		opts := []gate.StartOption{
+			gate.WithConfig(*cfg),
+		}
+		if !noConsole {
+			// Execute commands typed into the terminal
+			opts = append(opts, gate.WithConsole(os.Stdin, os.Stdout))
+		}
+		if v.ConfigFileUsed() != "" {
+			// Apply changes of the config file without restart
+			opts = append(opts, gate.WithAutoConfigReload(v))
+		}
+		if err = gate.Start(c.Context, opts...); err != nil {
 			return cli.Exit(fmt.Errorf("error running Gate: %w", err), 1)
 		}
 		return nil
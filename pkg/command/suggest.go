@@ -1,6 +1,13 @@
 package command
 
-import "go.minekube.com/brigodier"
+import (
+	"fmt"
+	"strings"
+
+	"go.minekube.com/brigodier"
+	"go.minekube.com/common/minecraft/component"
+	"go.minekube.com/common/minecraft/component/codec/legacy"
+)
 
 // SuggestFunc is a convenient function type implementing
 // the brigodier.SuggestionProvider interface.
@@ -15,3 +22,36 @@ func (s SuggestFunc) Suggestions(
 	b *brigodier.SuggestionsBuilder) *brigodier.Suggestions {
 	return s(createContext(c), b)
 }
+
+// Tooltip is a suggestion tooltip that shows a text component to 1.13+ clients.
+// Use it as the tooltip of a brigodier.Suggestion, other tooltips are shown as plain text.
+type Tooltip struct{ component.Component }
+
+var _ fmt.Stringer = (*Tooltip)(nil)
+
+// String returns the tooltip in legacy '§' format.
+func (t *Tooltip) String() string {
+	if t == nil || t.Component == nil {
+		return ""
+	}
+	b := new(strings.Builder)
+	_ = (&legacy.Legacy{}).Marshal(b, t.Component)
+	return b.String()
+}
+
+// TooltipComponent returns the text component of a suggestion tooltip or nil if tooltip is nil.
+func TooltipComponent(tooltip fmt.Stringer) component.Component {
+	switch t := tooltip.(type) {
+	case nil:
+		return nil
+	case *Tooltip:
+		if t == nil {
+			return nil
+		}
+		return t.Component
+	case component.Component:
+		return t
+	default:
+		return &component.Text{Content: t.String()}
+	}
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/common/minecraft/component"
+)
+
+type stringTooltip string
+
+func (s stringTooltip) String() string { return string(s) }
+
+func TestTooltip_String(t *testing.T) {
+	require.Equal(t, "", (*Tooltip)(nil).String())
+	require.Equal(t, "", (&Tooltip{}).String())
+	require.Equal(t, "Grüße 😀", (&Tooltip{Component: &component.Text{Content: "Grüße 😀"}}).String())
+}
+
+func TestTooltipComponent(t *testing.T) {
+	text := &component.Text{Content: "Grüße 😀"}
+	require.Nil(t, TooltipComponent(nil))
+	require.Nil(t, TooltipComponent((*Tooltip)(nil)))
+	require.Equal(t, text, TooltipComponent(&Tooltip{Component: text}))
+	require.Equal(t, text, TooltipComponent(stringTooltip("Grüße 😀")))
+}
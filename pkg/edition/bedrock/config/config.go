@@ -1,8 +1,100 @@
 package config
 
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"go.minekube.com/gate/pkg/util/validation"
+)
+
 // DefaultConfig is a default Config.
-var DefaultConfig = Config{}
+var DefaultConfig = Config{
+	Bind:       "0.0.0.0:19132",
+	OnlineMode: true,
+	Status: Status{
+		ShowMaxPlayers:  1000,
+		Motd:            "§bA Gate Proxy",
+		SubMotd:         "Gate",
+		LogPingRequests: false,
+	},
+	Compression: Compression{
+		Threshold: 256,
+		Level:     -1,
+	},
+	ReadTimeout: 30000,
+}
 
+// Config is the configuration of the Bedrock edition proxy.
 type Config struct {
+	Bind string // The address to listen for connections on (UDP).
+	// OnlineMode requires players to be logged into Xbox Live.
+	// Disabling it accepts self-signed logins and should only be used for testing.
+	OnlineMode bool
+
+	Status      Status
+	Compression Compression
+
 	ReadTimeout int // milliseconds
 }
+
+// Status is the status shown in the server list of Bedrock clients.
+type Status struct {
+	ShowMaxPlayers  int
+	Motd            string // The first line of the server list entry.
+	SubMotd         string // The second line, or the world name in the friends tab.
+	LogPingRequests bool
+}
+
+// Compression configures the compression of batches sent to clients.
+type Compression struct {
+	Threshold int // The minimum batch size in bytes clients compress.
+	Level     int // The flate compression level.
+}
+
+// Validate validates Config.
+func (c *Config) Validate() (warns []error, errs []error) {
+	e := func(m string, args ...any) { errs = append(errs, fmt.Errorf(m, args...)) }
+	w := func(m string, args ...any) { warns = append(warns, fmt.Errorf(m, args...)) }
+
+	if c == nil {
+		e("config must not be nil")
+		return
+	}
+
+	if len(c.Bind) == 0 {
+		e("Bind is empty")
+	} else {
+		if err := validation.ValidHostPort(c.Bind); err != nil {
+			e("Invalid bind %q: %v", c.Bind, err)
+		}
+	}
+
+	if !c.OnlineMode {
+		w("Bedrock edition proxy is running in offline mode, Xbox Live logins are not verified!")
+	}
+
+	if c.Status.ShowMaxPlayers < 0 {
+		e("Status.ShowMaxPlayers must not be negative")
+	}
+	// The pong data sent to clients separates its fields by semicolons
+	if strings.ContainsAny(c.Status.Motd, ";\n") {
+		e("Status.Motd must not contain semicolons or newlines")
+	}
+	if strings.ContainsAny(c.Status.SubMotd, ";\n") {
+		e("Status.SubMotd must not contain semicolons or newlines")
+	}
+
+	if c.Compression.Level < -1 || c.Compression.Level > 9 {
+		e("Unsupported compression level %d: must be -1..9", c.Compression.Level)
+	}
+	if c.Compression.Threshold < 0 || c.Compression.Threshold > math.MaxUint16 {
+		e("Invalid compression threshold %d: must be 0..%d", c.Compression.Threshold, math.MaxUint16)
+	}
+
+	if c.ReadTimeout <= 0 {
+		e("ReadTimeout must be positive")
+	}
+
+	return
+}
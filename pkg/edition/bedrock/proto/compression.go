@@ -0,0 +1,72 @@
+package proto
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CompressionPrefixProtocol is the first protocol (1.20.60) whose compressed
+// batches are prefixed with the id of the compression algorithm.
+const CompressionPrefixProtocol = 649
+
+// Compression algorithm ids prefixing batches.
+const (
+	flateCompressionID byte = 0x00
+	noCompressionID    byte = 0xff
+)
+
+// maxBatchSize is the maximum size of a decompressed batch.
+const maxBatchSize = 16 * 1024 * 1024
+
+// compression compresses or decompresses batches.
+type compression struct {
+	level  int
+	prefix bool // whether batches are prefixed with the algorithm id
+}
+
+func (c *compression) compress(data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if c.prefix {
+		buf.WriteByte(flateCompressionID)
+	}
+	w, err := flate.NewWriter(buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *compression) decompress(data []byte) ([]byte, error) {
+	if c.prefix {
+		if len(data) == 0 {
+			return nil, errors.New("missing compression algorithm")
+		}
+		switch data[0] {
+		case flateCompressionID:
+			data = data[1:]
+		case noCompressionID:
+			return data[1:], nil
+		default:
+			return nil, fmt.Errorf("unsupported compression algorithm %#x", data[0])
+		}
+	}
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	b, err := io.ReadAll(io.LimitReader(r, maxBatchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing batch: %w", err)
+	}
+	if len(b) > maxBatchSize {
+		return nil, errors.New("decompressed batch is too large")
+	}
+	return b, nil
+}
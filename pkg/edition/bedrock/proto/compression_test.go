@@ -0,0 +1,45 @@
+package proto
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression_roundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("batch "), 100)
+	for _, prefix := range []bool{false, true} {
+		c := &compression{level: flate.DefaultCompression, prefix: prefix}
+		compressed, err := c.compress(data)
+		require.NoError(t, err)
+		require.Less(t, len(compressed), len(data))
+		if prefix {
+			require.Equal(t, flateCompressionID, compressed[0])
+		}
+		decompressed, err := c.decompress(compressed)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+	}
+}
+
+func TestCompression_decompressPrefixed(t *testing.T) {
+	c := &compression{prefix: true}
+	b, err := c.decompress([]byte{noCompressionID, 1, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, b)
+
+	_, err = c.decompress(nil)
+	require.Error(t, err)
+	_, err = c.decompress([]byte{0x01, 1, 2, 3})
+	require.ErrorContains(t, err, "unsupported compression algorithm")
+}
+
+func TestCompression_tooLarge(t *testing.T) {
+	c := &compression{level: flate.BestSpeed}
+	compressed, err := c.compress(make([]byte, maxBatchSize+1))
+	require.NoError(t, err)
+	_, err = c.decompress(compressed)
+	require.ErrorContains(t, err, "too large")
+}
@@ -9,34 +9,64 @@ import (
 	"github.com/gammazero/deque"
 	"github.com/go-logr/logr"
 	"github.com/sandertv/go-raknet"
-	packetutil "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 
+	"go.minekube.com/gate/pkg/edition/bedrock/proto/util"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	"go.minekube.com/gate/pkg/gate/proto"
 	"go.minekube.com/gate/pkg/util/errs"
 )
 
+// batchHeader is the first byte of each batch of packets.
+const batchHeader = 0xfe
+
+// Decoder decodes the packets of batches read from a connection.
 type Decoder struct {
-	dec       *packetutil.Decoder
+	r         io.Reader
+	buf       []byte // read buffer if r is no packetReader
 	log       logr.Logger
 	direction proto.Direction
 	// not yet processed raw packets containing packet id + data
 	queue deque.Deque[[]byte]
 
+	encryption  *encryption  // nil-able
+	compression *compression // nil-able
+
 	// TODO move this pkg to common gate/proto
 	registry *state.ProtocolRegistry
 }
 
+// packetReader is implemented by raknet connections
+// to read a whole packet without copying.
+type packetReader interface {
+	ReadPacket() ([]byte, error)
+}
+
 func NewDecoder(
 	r io.Reader,
 	direction proto.Direction,
 	log logr.Logger,
 ) *Decoder {
-	return &Decoder{
-		dec:       packetutil.NewDecoder(r),
+	d := &Decoder{
+		r:         r,
 		log:       log,
 		direction: direction,
 	}
+	if _, ok := r.(packetReader); !ok {
+		d.buf = make([]byte, 3*1024*1024)
+	}
+	return d
+}
+
+// EnableEncryption decrypts all following batches with the key.
+func (d *Decoder) EnableEncryption(key [32]byte) {
+	d.encryption = newEncryption(key)
+}
+
+// EnableCompression decompresses all following batches.
+// If prefixed is true, batches start with the id of the
+// compression algorithm (see CompressionPrefixProtocol).
+func (d *Decoder) EnableCompression(prefixed bool) {
+	d.compression = &compression{prefix: prefixed}
 }
 
 func (d *Decoder) Decode() (*proto.PacketContext, error) {
@@ -50,12 +80,13 @@ func (d *Decoder) Decode() (*proto.PacketContext, error) {
 }
 
 func (d *Decoder) fillQueue() error {
-	// Read decrypted and decompressed packets from the underlying decoder.
-	rawPackets, err := d.dec.Decode()
+	// Read decrypted and decompressed packets of the next batch.
+	rawPackets, err := d.readBatch()
 	if err != nil {
-		if !raknet.ErrConnectionClosed(err) {
+		if raknet.ErrConnectionClosed(err) {
 			return io.EOF
 		}
+		return err
 	}
 
 	// Error out if we get too many packets and the server can't keep up.
@@ -70,10 +101,58 @@ func (d *Decoder) fillQueue() error {
 	return nil
 }
 
+func (d *Decoder) read() ([]byte, error) {
+	if pr, ok := d.r.(packetReader); ok {
+		return pr.ReadPacket()
+	}
+	n, err := d.r.Read(d.buf)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), d.buf[:n]...), nil
+}
+
+// readBatch reads the next batch and returns its packets.
+func (d *Decoder) readBatch() (packets [][]byte, err error) {
+	data, err := d.read()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] != batchHeader {
+		return nil, fmt.Errorf("invalid batch header %#x", data[0])
+	}
+	data = data[1:]
+	if d.encryption != nil {
+		if data, err = d.encryption.decrypt(data); err != nil {
+			return nil, err
+		}
+	}
+	if d.compression != nil {
+		if data, err = d.compression.decompress(data); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := bytes.NewBuffer(data)
+	for buf.Len() != 0 {
+		length, err := util.ReadVarUint32(buf)
+		if err != nil {
+			return nil, fmt.Errorf("error reading packet length: %w", err)
+		}
+		if int(length) > buf.Len() {
+			return nil, fmt.Errorf("packet length %d exceeds batch", length)
+		}
+		packets = append(packets, buf.Next(int(length)))
+	}
+	return packets, nil
+}
+
 func (d *Decoder) decode(p []byte) (ctx *proto.PacketContext, err error) {
 	ctx = &proto.PacketContext{
 		Direction:   d.direction,
-		Protocol:    d.registry.Protocol,
 		KnownPacket: false,
 		Payload:     p,
 	}
@@ -85,6 +164,10 @@ func (d *Decoder) decode(p []byte) (ctx *proto.PacketContext, err error) {
 		return nil, fmt.Errorf("error reading packet header: %w", err)
 	}
 	ctx.PacketID = proto.PacketID(header.PacketID)
+	if d.registry == nil {
+		return // no packets are known yet
+	}
+	ctx.Protocol = d.registry.Protocol
 
 	// Try find and create packet from the id.
 	ctx.Packet = d.registry.CreatePacket(ctx.PacketID)
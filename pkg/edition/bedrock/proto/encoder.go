@@ -1,24 +1,73 @@
 package proto
 
 import (
+	"bytes"
 	"io"
+	"sync"
 
 	"github.com/go-logr/logr"
-	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 
+	"go.minekube.com/gate/pkg/edition/bedrock/proto/util"
 	"go.minekube.com/gate/pkg/gate/proto"
 )
 
+// Encoder encodes packets into batches written to a connection.
+// It is safe for concurrent use.
 type Encoder struct {
-	dec       *packet.Encoder
+	w         io.Writer
 	log       logr.Logger
 	direction proto.Direction
+
+	mu          sync.Mutex   // Protects following fields and writes
+	encryption  *encryption  // nil-able
+	compression *compression // nil-able
 }
 
 func NewEncoder(w io.Writer, direction proto.Direction, log logr.Logger) *Encoder {
 	return &Encoder{
-		dec:       packet.NewEncoder(w),
+		w:         w,
 		log:       log,
 		direction: direction,
 	}
 }
+
+// EnableEncryption encrypts all following batches with the key.
+func (e *Encoder) EnableEncryption(key [32]byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.encryption = newEncryption(key)
+}
+
+// EnableCompression compresses all following batches with the flate level.
+// If prefixed is true, batches start with the id of the
+// compression algorithm (see CompressionPrefixProtocol).
+func (e *Encoder) EnableCompression(level int, prefixed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.compression = &compression{level: level, prefix: prefixed}
+}
+
+// Encode writes the packet payloads, each a Header followed
+// by the packet data, in one batch to the connection.
+func (e *Encoder) Encode(payloads ...[]byte) error {
+	buf := new(bytes.Buffer)
+	for _, payload := range payloads {
+		_ = util.WriteVarUint32(buf, uint32(len(payload)))
+		buf.Write(payload)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	data := buf.Bytes()
+	if e.compression != nil {
+		var err error
+		if data, err = e.compression.compress(data); err != nil {
+			return err
+		}
+	}
+	if e.encryption != nil {
+		data = e.encryption.encrypt(data)
+	}
+	_, err := e.w.Write(append([]byte{batchHeader}, data...))
+	return err
+}
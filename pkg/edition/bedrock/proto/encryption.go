@@ -0,0 +1,61 @@
+package proto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// encryption en- or decrypts the batches of one direction of a connection.
+//
+// The key exchange yields an AES-256-GCM key, but Bedrock edition uses it in
+// CTR mode with the IV of GCM and appends a SHA-256 checksum to each batch.
+type encryption struct {
+	key     []byte
+	stream  cipher.Stream
+	counter uint64 // batches processed
+}
+
+func newEncryption(key [32]byte) *encryption {
+	block, _ := aes.NewCipher(key[:]) // a 32 byte key is always valid
+	iv := append(append(make([]byte, 0, aes.BlockSize), key[:12]...), 0, 0, 0, 2)
+	return &encryption{
+		key:    append([]byte(nil), key[:]...),
+		stream: cipher.NewCTR(block, iv),
+	}
+}
+
+// checksum returns the checksum of the next batch.
+func (e *encryption) checksum(data []byte) []byte {
+	var counter [8]byte
+	binary.LittleEndian.PutUint64(counter[:], e.counter)
+	e.counter++
+	h := sha256.New()
+	h.Write(counter[:])
+	h.Write(data)
+	h.Write(e.key)
+	return h.Sum(nil)[:8]
+}
+
+// encrypt appends the checksum to data and encrypts it in place.
+func (e *encryption) encrypt(data []byte) []byte {
+	data = append(data, e.checksum(data)...)
+	e.stream.XORKeyStream(data, data)
+	return data
+}
+
+// decrypt decrypts data in place and returns it without the verified checksum.
+func (e *encryption) decrypt(data []byte) ([]byte, error) {
+	e.stream.XORKeyStream(data, data)
+	if len(data) < 8 {
+		return nil, errors.New("encrypted batch is too short")
+	}
+	payload, sum := data[:len(data)-8], data[len(data)-8:]
+	if !bytes.Equal(sum, e.checksum(payload)) {
+		return nil, errors.New("invalid checksum of encrypted batch")
+	}
+	return payload, nil
+}
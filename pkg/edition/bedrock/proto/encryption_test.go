@@ -0,0 +1,72 @@
+package proto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = func() (key [32]byte) {
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}()
+
+func TestEncryption_roundTrip(t *testing.T) {
+	enc, dec := newEncryption(testKey), newEncryption(testKey)
+	for _, batch := range []string{"first", "second batch", "", "third"} {
+		encrypted := enc.encrypt([]byte(batch))
+		require.Len(t, encrypted, len(batch)+8)
+		decrypted, err := dec.decrypt(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, batch, string(decrypted))
+	}
+	require.EqualValues(t, 4, enc.counter)
+	require.EqualValues(t, 4, dec.counter)
+}
+
+func TestEncryption_layout(t *testing.T) {
+	data := []byte("hello")
+	enc := newEncryption(testKey)
+	enc.encrypt([]byte("skipped"))
+	encrypted := enc.encrypt(append([]byte(nil), data...))
+
+	// CTR mode with the first 12 bytes of the key and a counter starting at 2 as IV
+	block, err := aes.NewCipher(testKey[:])
+	require.NoError(t, err)
+	iv := append(append([]byte(nil), testKey[:12]...), 0, 0, 0, 2)
+	plain := make([]byte, len("skipped")+len(encrypted)+8)
+	stream := cipher.NewCTR(block, iv)
+	stream.XORKeyStream(plain, append(make([]byte, len("skipped")+8), encrypted...))
+	plain = plain[len("skipped")+8:]
+	require.Equal(t, data, plain[:len(data)])
+
+	// SHA-256 checksum of the little endian batch counter, data and key
+	var counter [8]byte
+	binary.LittleEndian.PutUint64(counter[:], 1)
+	sum := sha256.Sum256(append(append(counter[:], data...), testKey[:]...))
+	require.Equal(t, sum[:8], plain[len(data):])
+}
+
+func TestEncryption_invalid(t *testing.T) {
+	enc, dec := newEncryption(testKey), newEncryption(testKey)
+	encrypted := enc.encrypt([]byte("hello"))
+	encrypted[0] ^= 1
+	_, err := dec.decrypt(encrypted)
+	require.Error(t, err)
+
+	// A skipped batch breaks the checksum counter
+	enc, dec = newEncryption(testKey), newEncryption(testKey)
+	enc.encrypt([]byte("lost"))
+	dec.stream.XORKeyStream(make([]byte, len("lost")+8), make([]byte, len("lost")+8))
+	_, err = dec.decrypt(enc.encrypt([]byte("hello")))
+	require.ErrorContains(t, err, "checksum")
+
+	_, err = newEncryption(testKey).decrypt([]byte{1, 2, 3})
+	require.Error(t, err)
+}
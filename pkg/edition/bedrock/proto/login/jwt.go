@@ -0,0 +1,117 @@
+package login
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// jwtHeader is the header of the ES384 signed JWTs used during the Bedrock edition login.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	X5U string `json:"x5u"` // base64 encoded public key of the signer
+}
+
+// token is a parsed, not yet verified JWT.
+type token struct {
+	header    jwtHeader
+	payload   []byte
+	signed    string // header.payload
+	signature []byte
+}
+
+var b64 = base64.RawURLEncoding
+
+func parseToken(s string) (*token, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("jwt must have 3 parts")
+	}
+	headerJSON, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding header: %w", err)
+	}
+	t := &token{signed: parts[0] + "." + parts[1]}
+	if err = json.Unmarshal(headerJSON, &t.header); err != nil {
+		return nil, fmt.Errorf("error unmarshaling header: %w", err)
+	}
+	if t.header.Alg != "ES384" {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", t.header.Alg)
+	}
+	if t.payload, err = b64.DecodeString(parts[1]); err != nil {
+		return nil, fmt.Errorf("error decoding payload: %w", err)
+	}
+	if t.signature, err = b64.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("error decoding signature: %w", err)
+	}
+	return t, nil
+}
+
+// verify verifies the signature of the token with key.
+func (t *token) verify(key *ecdsa.PublicKey) error {
+	if len(t.signature) != 96 {
+		return fmt.Errorf("invalid ES384 signature length %d", len(t.signature))
+	}
+	r := new(big.Int).SetBytes(t.signature[:48])
+	s := new(big.Int).SetBytes(t.signature[48:])
+	hash := sha512.Sum384([]byte(t.signed))
+	if !ecdsa.Verify(key, hash[:], r, s) {
+		return errors.New("invalid jwt signature")
+	}
+	return nil
+}
+
+// Sign returns an ES384 JWT of claims signed by key
+// with the public key of key in the x5u header.
+func Sign(key *ecdsa.PrivateKey, claims any) (string, error) {
+	header, err := json.Marshal(jwtHeader{Alg: "ES384", X5U: MarshalPublicKey(&key.PublicKey)})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	hash := sha512.Sum384([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	if err != nil {
+		return "", err
+	}
+	signature := make([]byte, 96)
+	r.FillBytes(signature[:48])
+	s.FillBytes(signature[48:])
+	return signed + "." + b64.EncodeToString(signature), nil
+}
+
+// ParsePublicKey parses a base64 encoded DER public key as used in the x5u
+// header and the identityPublicKey claim of the login chain.
+func ParsePublicKey(s string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding public key: %w", err)
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok || key.Curve != elliptic.P384() {
+		return nil, errors.New("public key must be an ECDSA P-384 key")
+	}
+	return key, nil
+}
+
+// MarshalPublicKey returns the base64 encoded DER form of key.
+func MarshalPublicKey(key *ecdsa.PublicKey) string {
+	der, _ := x509.MarshalPKIXPublicKey(key)
+	return base64.StdEncoding.EncodeToString(der)
+}
@@ -0,0 +1,230 @@
+// Package login parses and verifies the connection request
+// of the Login packet sent by Bedrock edition clients.
+package login
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.minekube.com/gate/pkg/util/uuid"
+)
+
+// RootPublicKey is the public key of the Xbox Live authentication service (issued by Mojang)
+// that signs the login chain of players logged into Xbox Live.
+var RootPublicKey = func() *ecdsa.PublicKey {
+	const key = "MHYwEAYHKoZIzj0CAQYFK4EEACIDYgAE8ELkixyLcwlZryUQcu1TvPOmI2B7vX83ndnWRUaXm74wFfa5f/lwQNTfrLVHa2PmenpGI6JhIMUJaWZrjmMj90NoKNFSNBuKdm8rYiXsfaz3K36x/1U26HpG0ZxK/V1V"
+	k, err := ParsePublicKey(key)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}()
+
+// IdentityData is the identity of a player. It is only
+// trustworthy if the Request was Authenticated, otherwise the XUID is empty.
+type IdentityData struct {
+	XUID        string `json:"XUID"` // Xbox user id, empty if not logged into Xbox Live.
+	Identity    string `json:"identity"`
+	DisplayName string `json:"displayName"`
+	TitleID     string `json:"titleId"` // Xbox Live title id of the client's platform.
+}
+
+// ClientData is client specific data like the device info,
+// it is signed by the client itself and can be spoofed.
+type ClientData struct {
+	DeviceOS         int    `json:"DeviceOS"`
+	DeviceModel      string `json:"DeviceModel"`
+	DeviceID         string `json:"DeviceId"`
+	GameVersion      string `json:"GameVersion"`
+	LanguageCode     string `json:"LanguageCode"`
+	ServerAddress    string `json:"ServerAddress"`
+	ThirdPartyName   string `json:"ThirdPartyName"`
+	CurrentInputMode int    `json:"CurrentInputMode"`
+	DefaultInputMode int    `json:"DefaultInputMode"`
+	UIProfile        int    `json:"UIProfile"`
+}
+
+// Request is a parsed and verified connection request.
+type Request struct {
+	Identity IdentityData
+	Client   ClientData
+	// PublicKey is the client's key for the key exchange to enable encryption.
+	PublicKey *ecdsa.PublicKey
+	// Authenticated is true if the identity was signed by the root key,
+	// meaning the player is logged into Xbox Live.
+	Authenticated bool
+}
+
+type chainClaims struct {
+	IdentityPublicKey string        `json:"identityPublicKey"`
+	ExtraData         *IdentityData `json:"extraData"`
+	ExpiresAt         int64         `json:"exp"`
+	NotBefore         int64         `json:"nbf"`
+}
+
+// clockSkew is the tolerated difference between the clocks of client and proxy.
+const clockSkew = time.Minute
+
+func (c *chainClaims) validate(now time.Time) error {
+	if c.ExpiresAt != 0 && now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return errors.New("token is expired")
+	}
+	if c.NotBefore != 0 && now.Before(time.Unix(c.NotBefore, 0).Add(-clockSkew)) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
+
+// Parse parses the connection request of a Login packet and verifies its chain
+// and client data signatures. The Request is Authenticated if one of the chain
+// tokens before the identity data is signed by rootKey.
+func Parse(connectionRequest []byte, rootKey *ecdsa.PublicKey) (*Request, error) {
+	chain, rawToken, err := decodeRequest(connectionRequest)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 || len(chain) > 3 {
+		return nil, fmt.Errorf("unexpected login chain length %d", len(chain))
+	}
+
+	var (
+		now       = time.Now()
+		key       *ecdsa.PublicKey
+		req       = new(Request)
+		rootIndex = -1
+		identity  *IdentityData
+	)
+	for i, raw := range chain {
+		t, err := parseToken(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing chain token %d: %w", i, err)
+		}
+		if i == 0 {
+			// The first token is self-signed by the client
+			if key, err = ParsePublicKey(t.header.X5U); err != nil {
+				return nil, fmt.Errorf("error parsing x5u of chain token 0: %w", err)
+			}
+		}
+		if err = t.verify(key); err != nil {
+			return nil, fmt.Errorf("error verifying chain token %d: %w", i, err)
+		}
+		if rootIndex == -1 && rootKey != nil && key.Equal(rootKey) {
+			rootIndex = i
+		}
+		var claims chainClaims
+		if err = json.Unmarshal(t.payload, &claims); err != nil {
+			return nil, fmt.Errorf("error unmarshaling chain token %d: %w", i, err)
+		}
+		if err = claims.validate(now); err != nil {
+			return nil, fmt.Errorf("invalid chain token %d: %w", i, err)
+		}
+		if claims.ExtraData != nil {
+			identity = claims.ExtraData
+			req.Authenticated = rootIndex != -1
+		}
+		if key, err = ParsePublicKey(claims.IdentityPublicKey); err != nil {
+			return nil, fmt.Errorf("error parsing identityPublicKey of chain token %d: %w", i, err)
+		}
+	}
+	if identity == nil {
+		return nil, errors.New("login chain has no identity data")
+	}
+	req.Identity = *identity
+	if !req.Authenticated {
+		req.Identity.XUID = "" // can not be trusted
+	}
+	req.PublicKey = key
+
+	t, err := parseToken(rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing client data: %w", err)
+	}
+	if err = t.verify(key); err != nil {
+		return nil, fmt.Errorf("error verifying client data: %w", err)
+	}
+	if err = json.Unmarshal(t.payload, &req.Client); err != nil {
+		return nil, fmt.Errorf("error unmarshaling client data: %w", err)
+	}
+
+	if err = req.Identity.validate(req.Authenticated); err != nil {
+		return nil, fmt.Errorf("invalid identity data: %w", err)
+	}
+	return req, nil
+}
+
+func (d *IdentityData) validate(authenticated bool) error {
+	if _, err := uuid.Parse(d.Identity); err != nil {
+		return fmt.Errorf("invalid identity %q: %w", d.Identity, err)
+	}
+	if d.DisplayName == "" || len(d.DisplayName) > 16 {
+		return fmt.Errorf("invalid display name %q", d.DisplayName)
+	}
+	if authenticated {
+		if _, err := strconv.ParseInt(d.XUID, 10, 64); err != nil {
+			return fmt.Errorf("invalid XUID %q: %w", d.XUID, err)
+		}
+	}
+	return nil
+}
+
+// decodeRequest decodes the login chain and the raw client data token of a connection request.
+func decodeRequest(b []byte) (chain []string, rawToken string, err error) {
+	buf := bytes.NewBuffer(b)
+	chainJSON, err := readString(buf)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading login chain: %w", err)
+	}
+	var c struct {
+		Chain []string `json:"chain"`
+		// Since 1.21.90 the chain is nested in the certificate
+		Certificate string `json:"Certificate"`
+	}
+	if err = json.Unmarshal(chainJSON, &c); err != nil {
+		return nil, "", fmt.Errorf("error unmarshaling login chain: %w", err)
+	}
+	if c.Certificate != "" {
+		if err = json.Unmarshal([]byte(c.Certificate), &c); err != nil {
+			return nil, "", fmt.Errorf("error unmarshaling login chain certificate: %w", err)
+		}
+	}
+	token, err := readString(buf)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading client data: %w", err)
+	}
+	return c.Chain, string(token), nil
+}
+
+// maxStringLength is the maximum length of a string in a connection request.
+const maxStringLength = 1 << 22
+
+// readString reads a string prefixed by its little endian int32 length.
+func readString(buf *bytes.Buffer) ([]byte, error) {
+	var length int32
+	if err := binary.Read(buf, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 0 || length > maxStringLength || int(length) > buf.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return buf.Next(int(length)), nil
+}
+
+// EncodeRequest encodes a connection request of the chain and client data token.
+func EncodeRequest(chain []string, rawToken string) []byte {
+	chainJSON, _ := json.Marshal(struct {
+		Chain []string `json:"chain"`
+	}{Chain: chain})
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, int32(len(chainJSON)))
+	buf.Write(chainJSON)
+	_ = binary.Write(buf, binary.LittleEndian, int32(len(rawToken)))
+	buf.WriteString(rawToken)
+	return buf.Bytes()
+}
@@ -0,0 +1,82 @@
+package login
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, claims map[string]any) string {
+	claims["nbf"] = time.Now().Add(-time.Hour).Unix()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	s, err := Sign(key, claims)
+	require.NoError(t, err)
+	return s
+}
+
+var identity = map[string]any{
+	"XUID":        "2535428719841234",
+	"identity":    "6b8b4567-327b-23c6-643c-986966334873",
+	"displayName": "Steve",
+	"titleId":     "896928775",
+}
+
+func clientData(t *testing.T, key *ecdsa.PrivateKey) string {
+	return sign(t, key, map[string]any{"DeviceOS": 7, "DeviceModel": "PC", "GameVersion": "1.20.0"})
+}
+
+func TestParse_Authenticated(t *testing.T) {
+	root, intermediate, client := newKey(t), newKey(t), newKey(t)
+	chain := []string{
+		sign(t, client, map[string]any{"identityPublicKey": MarshalPublicKey(&root.PublicKey)}),
+		sign(t, root, map[string]any{"identityPublicKey": MarshalPublicKey(&intermediate.PublicKey)}),
+		sign(t, intermediate, map[string]any{
+			"identityPublicKey": MarshalPublicKey(&client.PublicKey),
+			"extraData":         identity,
+		}),
+	}
+	req, err := Parse(EncodeRequest(chain, clientData(t, client)), &root.PublicKey)
+	require.NoError(t, err)
+	require.True(t, req.Authenticated)
+	require.Equal(t, "Steve", req.Identity.DisplayName)
+	require.Equal(t, "2535428719841234", req.Identity.XUID)
+	require.Equal(t, 7, req.Client.DeviceOS)
+	require.True(t, req.PublicKey.Equal(&client.PublicKey))
+
+	// Not signed by the root key
+	req, err = Parse(EncodeRequest(chain, clientData(t, client)), &newKey(t).PublicKey)
+	require.NoError(t, err)
+	require.False(t, req.Authenticated)
+	require.Empty(t, req.Identity.XUID)
+}
+
+func TestParse_SelfSigned(t *testing.T) {
+	client := newKey(t)
+	offline := map[string]any{}
+	for k, v := range identity {
+		offline[k] = v
+	}
+	offline["XUID"] = ""
+	chain := []string{sign(t, client, map[string]any{
+		"identityPublicKey": MarshalPublicKey(&client.PublicKey),
+		"extraData":         offline,
+	})}
+	req, err := Parse(EncodeRequest(chain, clientData(t, client)), RootPublicKey)
+	require.NoError(t, err)
+	require.False(t, req.Authenticated)
+	require.Equal(t, "Steve", req.Identity.DisplayName)
+
+	// Client data signed by another key
+	_, err = Parse(EncodeRequest(chain, clientData(t, newKey(t))), RootPublicKey)
+	require.Error(t, err)
+}
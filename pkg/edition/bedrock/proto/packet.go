@@ -1,9 +1,15 @@
 package proto
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+
 	"go.minekube.com/gate/pkg/edition/bedrock/proto/util"
 	"go.minekube.com/gate/pkg/gate/proto"
-	"io"
 )
 
 // PacketMeta is a Bedrock edition packet.
@@ -38,3 +44,36 @@ func (h *Header) Decode(_ *proto.PacketContext, rd io.Reader) error {
 }
 
 var _ proto.Packet = (*Header)(nil)
+
+// MarshalPacket returns the payload of a packet, its Header followed by the packet data.
+func MarshalPacket(pk packet.Packet) []byte {
+	buf := new(bytes.Buffer)
+	h := Header{PacketID: pk.ID()}
+	_ = h.Encode(nil, buf)
+	pk.Marshal(protocol.NewWriter(buf, 0))
+	return buf.Bytes()
+}
+
+// UnmarshalPacket decodes the payload of a packet into pk.
+// The packet id of the payload must be the one of pk.
+func UnmarshalPacket(payload []byte, pk packet.Packet) (err error) {
+	buf := bytes.NewBuffer(payload)
+	var h Header
+	if err = h.Decode(nil, buf); err != nil {
+		return fmt.Errorf("error reading packet header: %w", err)
+	}
+	if h.PacketID != pk.ID() {
+		return fmt.Errorf("unexpected packet id %d, expected %d", h.PacketID, pk.ID())
+	}
+	// The protocol.Reader panics on invalid data
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error decoding packet %T: %v", pk, r)
+		}
+	}()
+	pk.Unmarshal(protocol.NewReader(buf, 0))
+	if buf.Len() != 0 {
+		return fmt.Errorf("packet %T has %d unread bytes: %w", pk, buf.Len(), proto.ErrDecoderLeftBytes)
+	}
+	return nil
+}
@@ -1 +1,63 @@
+// Package packet contains Bedrock edition packets that the
+// protocol/packet package of gophertunnel does not provide.
 package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Packet ids.
+const (
+	IDNetworkSettings        uint32 = 0x8f
+	IDRequestNetworkSettings uint32 = 0xc1
+)
+
+// RequestNetworkSettings is the first packet sent by clients
+// of protocol 554 (1.19.30) and newer, before the Login packet.
+type RequestNetworkSettings struct {
+	ClientProtocol int32
+}
+
+func (*RequestNetworkSettings) ID() uint32 { return IDRequestNetworkSettings }
+
+func (pk *RequestNetworkSettings) Marshal(w *protocol.Writer) {
+	w.BEInt32(&pk.ClientProtocol)
+}
+
+func (pk *RequestNetworkSettings) Unmarshal(r *protocol.Reader) {
+	r.BEInt32(&pk.ClientProtocol)
+}
+
+// NetworkSettings is sent in reply to RequestNetworkSettings
+// and enables compression for all following batches.
+type NetworkSettings struct {
+	CompressionThreshold    uint16
+	CompressionAlgorithm    uint16
+	ClientThrottle          bool
+	ClientThrottleThreshold uint8
+	ClientThrottleScalar    float32
+}
+
+// Compression algorithms of NetworkSettings.
+const (
+	FlateCompression  uint16 = 0
+	SnappyCompression uint16 = 1
+)
+
+func (*NetworkSettings) ID() uint32 { return IDNetworkSettings }
+
+func (pk *NetworkSettings) Marshal(w *protocol.Writer) {
+	w.Uint16(&pk.CompressionThreshold)
+	w.Uint16(&pk.CompressionAlgorithm)
+	w.Bool(&pk.ClientThrottle)
+	w.Uint8(&pk.ClientThrottleThreshold)
+	w.Float32(&pk.ClientThrottleScalar)
+}
+
+func (pk *NetworkSettings) Unmarshal(r *protocol.Reader) {
+	r.Uint16(&pk.CompressionThreshold)
+	r.Uint16(&pk.CompressionAlgorithm)
+	r.Bool(&pk.ClientThrottle)
+	r.Uint8(&pk.ClientThrottleThreshold)
+	r.Float32(&pk.ClientThrottleScalar)
+}
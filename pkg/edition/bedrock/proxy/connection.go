@@ -1,12 +1,13 @@
 package proxy
 
 import (
-	"bufio"
 	"io"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 
 	"go.minekube.com/gate/pkg/edition/bedrock/config"
 	"go.minekube.com/gate/pkg/edition/bedrock/proto"
@@ -15,6 +16,8 @@ import (
 
 type sessionHandler interface {
 	handlePacket(p *protoutil.PacketContext)
+	// disconnected is called when the connection was closed.
+	disconnected()
 }
 
 type minecraftConn struct {
@@ -22,13 +25,12 @@ type minecraftConn struct {
 	log   logr.Logger
 	c     net.Conn
 
-	rwBuf   *bufio.ReadWriter
 	decoder *proto.Decoder
 	encoder *proto.Encoder
 
-	mu sync.RWMutex // Protects following fields
-	// state
-	// sessionHandler
+	mu             sync.RWMutex // Protects following fields
+	sessionHandler sessionHandler
+	closed         bool
 }
 
 func newMinecraftConn(base net.Conn, proxy *Proxy, isPlayer bool) (conn *minecraftConn) {
@@ -42,27 +44,30 @@ func newMinecraftConn(base net.Conn, proxy *Proxy, isPlayer bool) (conn *minecra
 	}
 
 	log := proxy.log.WithName(logName).WithValues("remoteAddr", base.RemoteAddr())
-	rwBuf := &bufio.ReadWriter{
-		Reader: bufio.NewReader(base),
-		Writer: bufio.NewWriter(base),
-	}
+	// Each read and write of a raknet connection is a whole batch,
+	// so the codec must not use buffered readers and writers.
 	return &minecraftConn{
 		proxy:   proxy,
 		log:     log,
 		c:       base,
-		rwBuf:   rwBuf,
-		decoder: proto.NewDecoder(rwBuf.Reader, in, log.WithName("decoder")),
-		encoder: proto.NewEncoder(rwBuf.Writer, out, log.WithName("encoder")),
+		decoder: proto.NewDecoder(base, in, log.WithName("decoder")),
+		encoder: proto.NewEncoder(base, out, log.WithName("encoder")),
 	}
 }
 
 func (c *minecraftConn) readLoop() {
-	//defer c.close()
+	defer func() {
+		_ = c.close()
+		if h := c.handler(); h != nil {
+			h.disconnected()
+		}
+	}()
 
-	//// Set read timeout to wait for client to send packet/s
-	//deadline := time.Now().Add(time.Duration(c.config().ReadTimeout) * time.Millisecond)
-	//_ = c.c.SetReadDeadline(deadline)
 	for {
+		// Set read timeout to wait for client to send packet/s
+		deadline := time.Now().Add(time.Duration(c.config().ReadTimeout) * time.Millisecond)
+		_ = c.c.SetReadDeadline(deadline)
+
 		packetCtx, err := c.decoder.Decode()
 		if err != nil {
 			if err != io.EOF { // EOF means connection was closed
@@ -70,11 +75,55 @@ func (c *minecraftConn) readLoop() {
 			}
 			return
 		}
-		// TODO
-		_ = packetCtx
+		if c.isClosed() {
+			return
+		}
+		if h := c.handler(); h != nil {
+			h.handlePacket(packetCtx)
+		}
 	}
 }
 
+// writePacket writes a packet in its own batch.
+func (c *minecraftConn) writePacket(pk packet.Packet) error {
+	return c.encoder.Encode(proto.MarshalPacket(pk))
+}
+
+// disconnect sends the client a disconnect screen with
+// the message and closes the connection.
+func (c *minecraftConn) disconnect(message string) {
+	if err := c.writePacket(&packet.Disconnect{Message: message}); err != nil {
+		c.log.V(1).Info("error writing disconnect packet", "err", err)
+	}
+	_ = c.close()
+}
+
+// close closes the underlying connection.
+func (c *minecraftConn) close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.c.Close()
+}
+
+func (c *minecraftConn) isClosed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.closed
+}
+
+func (c *minecraftConn) handler() sessionHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionHandler
+}
+
+func (c *minecraftConn) setSessionHandler(h sessionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionHandler = h
+}
+
 func (c *minecraftConn) config() *config.Config {
 	return c.proxy.config
 }
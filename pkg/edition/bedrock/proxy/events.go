@@ -0,0 +1,111 @@
+package proxy
+
+import "net"
+
+// PingEvent is fired when a Bedrock edition client
+// pings the proxy to show its status in the server list.
+//
+// It is fired in the listener's read loop,
+// subscribers must therefore return quickly.
+type PingEvent struct {
+	remoteAddr net.Addr
+	pong       *Pong
+}
+
+// RemoteAddr returns the address of the pinging client.
+func (p *PingEvent) RemoteAddr() net.Addr {
+	return p.remoteAddr
+}
+
+// Pong returns the used pong. (pre-initialized by the proxy)
+func (p *PingEvent) Pong() *Pong {
+	return p.pong
+}
+
+// SetPong sets the pong response to use.
+func (p *PingEvent) SetPong(pong *Pong) {
+	p.pong = pong
+}
+
+//
+//
+//
+//
+//
+
+// ReadyEvent is fired once the proxy was successfully
+// initialized and is ready to serve connections.
+type ReadyEvent struct{}
+
+//
+//
+//
+//
+//
+
+// LoginEvent is fired after a player logged in and enabled
+// encryption, but before the player is registered with the proxy.
+type LoginEvent struct {
+	player *Player
+	denied bool
+	reason string
+}
+
+// Player returns the player who logged in.
+func (e *LoginEvent) Player() *Player {
+	return e.player
+}
+
+// Allowed returns true if the player is allowed to join the proxy.
+func (e *LoginEvent) Allowed() bool {
+	return !e.denied
+}
+
+// Reason returns the message shown to the player if the login was denied.
+func (e *LoginEvent) Reason() string {
+	return e.reason
+}
+
+// Deny denies the player to join the proxy showing the reason.
+func (e *LoginEvent) Deny(reason string) {
+	e.denied = true
+	e.reason = reason
+}
+
+// Allow allows the player to join the proxy.
+func (e *LoginEvent) Allow() {
+	e.denied = false
+	e.reason = ""
+}
+
+//
+//
+//
+//
+//
+
+// PostLoginEvent is fired after a player was registered with the proxy.
+type PostLoginEvent struct {
+	player *Player
+}
+
+// Player returns the player who logged in.
+func (e *PostLoginEvent) Player() *Player {
+	return e.player
+}
+
+//
+//
+//
+//
+//
+
+// DisconnectEvent is fired when a registered player disconnected.
+type DisconnectEvent struct {
+	player *Player
+}
+
+// Player returns the disconnected player.
+func (e *DisconnectEvent) Player() *Player {
+	return e.player
+}
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+
+	"go.minekube.com/gate/pkg/util/netutil"
+)
+
+// Pong is the status a Bedrock edition client shows in the server list.
+// It is sent in reply to unconnected pings of the RakNet protocol.
+type Pong struct {
+	Motd            string // The first line of the server list entry.
+	SubMotd         string // The second line, or the world name in the friends tab.
+	ProtocolVersion int
+	Version         string // The Minecraft version name.
+	PlayerCount     int
+	MaxPlayers      int
+	ServerGUID      int64
+	GameMode        string
+	GameModeNumeric int
+	PortV4          uint16
+	PortV6          uint16
+}
+
+// MarshalText returns the pong data in the semicolon separated format of Bedrock edition.
+func (p *Pong) MarshalText() ([]byte, error) {
+	fields := []string{
+		"MCPE",
+		pongEscaper.Replace(p.Motd),
+		strconv.Itoa(p.ProtocolVersion),
+		pongEscaper.Replace(p.Version),
+		strconv.Itoa(p.PlayerCount),
+		strconv.Itoa(p.MaxPlayers),
+		strconv.FormatInt(p.ServerGUID, 10),
+		pongEscaper.Replace(p.SubMotd),
+		pongEscaper.Replace(p.GameMode),
+		strconv.Itoa(p.GameModeNumeric),
+		strconv.Itoa(int(p.PortV4)),
+		strconv.Itoa(int(p.PortV6)),
+	}
+	return []byte(strings.Join(fields, ";") + ";"), nil
+}
+
+// pongEscaper removes the field separator from pong fields
+// so that a plugin-set motd can not shift the other fields.
+var pongEscaper = strings.NewReplacer(";", "", "\n", " ")
+
+// newPong returns the pong initialized from the proxy's config.
+func (p *Proxy) newPong(serverGUID int64, addr net.Addr) *Pong {
+	port := netutil.Port(addr)
+	return &Pong{
+		Motd:            p.config.Status.Motd,
+		SubMotd:         p.config.Status.SubMotd,
+		ProtocolVersion: protocol.CurrentProtocol,
+		Version:         protocol.CurrentVersion,
+		PlayerCount:     p.PlayerCount(),
+		MaxPlayers:      p.config.Status.ShowMaxPlayers,
+		ServerGUID:      serverGUID,
+		GameMode:        "Survival",
+		GameModeNumeric: 1,
+		PortV4:          port,
+		PortV6:          port,
+	}
+}
+
+// Packet ids of RakNet unconnected pings.
+const (
+	idUnconnectedPing                = 0x01
+	idUnconnectedPingOpenConnections = 0x02
+)
+
+// pingListener is a raknet.UpstreamPacketListener calling onPing for every unconnected
+// ping before the raknet.Listener reads it, so that the pong data can be updated in time.
+type pingListener struct {
+	onPing func(addr net.Addr)
+}
+
+func (l *pingListener) ListenPacket(network, address string) (net.PacketConn, error) {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &pingConn{PacketConn: conn, onPing: l.onPing}, nil
+}
+
+type pingConn struct {
+	net.PacketConn
+	onPing func(addr net.Addr)
+}
+
+func (c *pingConn) ReadFrom(b []byte) (n int, addr net.Addr, err error) {
+	n, addr, err = c.PacketConn.ReadFrom(b)
+	if err == nil && n != 0 && (b[0] == idUnconnectedPing || b[0] == idUnconnectedPingOpenConnections) {
+		c.onPing(addr)
+	}
+	return n, addr, err
+}
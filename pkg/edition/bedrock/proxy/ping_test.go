@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPong_MarshalText(t *testing.T) {
+	tests := []struct {
+		name string
+		pong Pong
+		want string
+	}{
+		{
+			name: "all fields",
+			pong: Pong{
+				Motd:            "A Gate Proxy",
+				SubMotd:         "Gate",
+				ProtocolVersion: 594,
+				Version:         "1.20.10",
+				PlayerCount:     3,
+				MaxPlayers:      100,
+				ServerGUID:      1234567890,
+				GameMode:        "Survival",
+				GameModeNumeric: 1,
+				PortV4:          19132,
+				PortV6:          19133,
+			},
+			want: "MCPE;A Gate Proxy;594;1.20.10;3;100;1234567890;Gate;Survival;1;19132;19133;",
+		},
+		{
+			name: "zero",
+			want: "MCPE;;0;;0;0;0;;;0;0;0;",
+		},
+		{
+			name: "escape separators",
+			pong: Pong{
+				Motd:     "first;line\nsecond",
+				SubMotd:  ";;",
+				Version:  "1.20;0",
+				GameMode: "Sur\nvival",
+			},
+			want: "MCPE;firstline second;0;1.200;0;0;0;;Sur vival;0;0;0;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.pong.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestPingConn_ReadFrom(t *testing.T) {
+	pings := make(chan net.Addr, 10)
+	l := &pingListener{onPing: func(addr net.Addr) { pings <- addr }}
+	conn, err := l.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer client.Close()
+
+	tests := []struct {
+		name   string
+		packet []byte
+		ping   bool
+	}{
+		{name: "unconnected ping", packet: []byte{idUnconnectedPing, 1, 2, 3}, ping: true},
+		{name: "unconnected ping open connections", packet: []byte{idUnconnectedPingOpenConnections, 1}, ping: true},
+		{name: "open connection request", packet: []byte{0x05, 1, 2, 3}},
+		{name: "empty", packet: []byte{}},
+	}
+	buf := make([]byte, 1500)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.WriteTo(tt.packet, conn.LocalAddr())
+			require.NoError(t, err)
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+			n, addr, err := conn.ReadFrom(buf)
+			require.NoError(t, err)
+			require.Equal(t, tt.packet, buf[:n])
+			require.Equal(t, client.LocalAddr().String(), addr.String())
+			if tt.ping {
+				require.Len(t, pings, 1)
+				require.Equal(t, client.LocalAddr().String(), (<-pings).String())
+			} else {
+				require.Empty(t, pings)
+			}
+		})
+	}
+}
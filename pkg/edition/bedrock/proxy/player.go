@@ -1,4 +1,75 @@
 package proxy
 
+import (
+	"net"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+
+	"go.minekube.com/gate/pkg/edition/bedrock/proto/login"
+	"go.minekube.com/gate/pkg/util/uuid"
+)
+
+// Player is a Bedrock edition player that logged in to the proxy.
 type Player struct {
+	conn          *minecraftConn
+	id            uuid.UUID
+	protocol      int32
+	identity      login.IdentityData
+	client        login.ClientData
+	authenticated bool
+}
+
+func newPlayer(conn *minecraftConn, protocol int32, req *login.Request) (*Player, error) {
+	id, err := uuid.Parse(req.Identity.Identity)
+	if err != nil {
+		return nil, err
+	}
+	return &Player{
+		conn:          conn,
+		id:            id,
+		protocol:      protocol,
+		identity:      req.Identity,
+		client:        req.Client,
+		authenticated: req.Authenticated,
+	}, nil
 }
+
+// ID returns the unique id of the player.
+func (p *Player) ID() uuid.UUID { return p.id }
+
+// XUID returns the Xbox user id of the player or an empty string if not Authenticated.
+func (p *Player) XUID() string { return p.identity.XUID }
+
+// DisplayName returns the player's Xbox Live gamertag.
+// It may change and should not be used as a key.
+func (p *Player) DisplayName() string { return p.identity.DisplayName }
+
+// Authenticated returns true if the player is logged into Xbox Live.
+// It is always true if the proxy is in online mode.
+func (p *Player) Authenticated() bool { return p.authenticated }
+
+// Protocol returns the protocol version of the player's client.
+func (p *Player) Protocol() int32 { return p.protocol }
+
+// GameVersion returns the Minecraft version of the player's client, e.g. "1.20.10".
+func (p *Player) GameVersion() string { return p.client.GameVersion }
+
+// DeviceOS returns the operating system of the player's device.
+func (p *Player) DeviceOS() protocol.DeviceOS { return protocol.DeviceOS(p.client.DeviceOS) }
+
+// DeviceModel returns the model of the player's device as reported by the client.
+func (p *Player) DeviceModel() string { return p.client.DeviceModel }
+
+// DeviceID returns the id of the player's device as reported by the client.
+func (p *Player) DeviceID() string { return p.client.DeviceID }
+
+// LanguageCode returns the player's language, e.g. "en_US".
+func (p *Player) LanguageCode() string { return p.client.LanguageCode }
+
+// RemoteAddr returns the player's address.
+func (p *Player) RemoteAddr() net.Addr { return p.conn.c.RemoteAddr() }
+
+// Disconnect disconnects the player showing the message.
+func (p *Player) Disconnect(message string) { p.conn.disconnect(message) }
+
+func (p *Player) String() string { return p.identity.DisplayName }
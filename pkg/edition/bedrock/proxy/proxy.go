@@ -5,10 +5,15 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	stdlog "log"
+	"math"
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/sandertv/go-raknet"
@@ -16,6 +21,7 @@ import (
 	"go.minekube.com/gate/pkg/edition/bedrock/config"
 	"go.minekube.com/gate/pkg/runtime/event"
 	"go.minekube.com/gate/pkg/util/errs"
+	"go.minekube.com/gate/pkg/util/uuid"
 )
 
 // Options are the options for a new Bedrock edition Proxy.
@@ -67,14 +73,110 @@ type Proxy struct {
 	closeListener chan struct{}
 	started       bool
 
+	muC   sync.RWMutex // Protects following field
+	conns map[*minecraftConn]struct{}
+
+	muP     sync.RWMutex // Protects following field
+	players map[uuid.UUID]*Player
+
 	listenerKey *ecdsa.PrivateKey
 }
 
+// ErrProxyAlreadyRun is returned by Proxy.Start if the proxy instance was already run.
+var ErrProxyAlreadyRun = errors.New("proxy was already run, create a new one")
+
+// Start runs the proxy and blocks until the proxy is
+// shut down or an error occurred while listening.
 func (p *Proxy) Start(ctx context.Context) error {
-	<-ctx.Done()
+	p.closeMu.Lock()
+	if p.started {
+		p.closeMu.Unlock()
+		return ErrProxyAlreadyRun
+	}
+	p.started = true
+	p.startTime.Store(time.Now())
 	p.log = logr.FromContextOrDiscard(ctx)
-	// TODO
-	return nil
+
+	stopListener := make(chan struct{})
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		p.Shutdown()
+	}()
+	p.closeListener = stopListener
+	p.closeMu.Unlock()
+
+	defer p.Shutdown()
+	return p.listenAndServe(p.config.Bind, stopListener)
+}
+
+// Shutdown stops the Proxy and closes all connections.
+func (p *Proxy) Shutdown() {
+	p.closeMu.Lock()
+	defer p.closeMu.Unlock()
+	if !p.started {
+		return // not started or already shutdown
+	}
+	p.started = false
+	close(p.closeListener) // stop listening for new connections
+
+	p.muC.RLock()
+	for conn := range p.conns {
+		_ = conn.close()
+	}
+	p.muC.RUnlock()
+}
+
+// PlayerCount returns the number of players logged in to the proxy.
+func (p *Proxy) PlayerCount() int {
+	p.muP.RLock()
+	defer p.muP.RUnlock()
+	return len(p.players)
+}
+
+// Players returns all players logged in to the proxy.
+func (p *Proxy) Players() []*Player {
+	p.muP.RLock()
+	defer p.muP.RUnlock()
+	players := make([]*Player, 0, len(p.players))
+	for _, player := range p.players {
+		players = append(players, player)
+	}
+	return players
+}
+
+// Player returns the player by id or nil if not logged in.
+func (p *Proxy) Player(id uuid.UUID) *Player {
+	p.muP.RLock()
+	defer p.muP.RUnlock()
+	return p.players[id]
+}
+
+// registerPlayer registers the player and
+// returns false if a player with the same id exists.
+func (p *Proxy) registerPlayer(player *Player) bool {
+	p.muP.Lock()
+	defer p.muP.Unlock()
+	if _, exists := p.players[player.ID()]; exists {
+		return false
+	}
+	if p.players == nil {
+		p.players = map[uuid.UUID]*Player{}
+	}
+	p.players[player.ID()] = player
+	return true
+}
+
+// unregisterPlayer unregisters the player and returns true if it was registered.
+func (p *Proxy) unregisterPlayer(player *Player) bool {
+	p.muP.Lock()
+	defer p.muP.Unlock()
+	if p.players[player.ID()] != player {
+		return false
+	}
+	delete(p.players, player.ID())
+	return true
 }
 
 func (p *Proxy) listenAndServe(addr string, stop <-chan struct{}) error {
@@ -84,26 +186,92 @@ func (p *Proxy) listenAndServe(addr string, stop <-chan struct{}) error {
 	default:
 	}
 
-	ln, err := raknet.Listen(addr)
+	var listener *raknet.Listener
+	var listenerMu sync.RWMutex
+	ln, err := raknet.ListenConfig{
+		ErrorLog: stdlog.New(io.Discard, "", 0),
+		UpstreamPacketListener: &pingListener{onPing: func(remoteAddr net.Addr) {
+			listenerMu.RLock()
+			defer listenerMu.RUnlock()
+			if listener != nil {
+				p.updatePong(listener, remoteAddr)
+			}
+		}},
+	}.Listen(addr)
 	if err != nil {
 		return err
 	}
+	listenerMu.Lock()
+	listener = ln
+	listenerMu.Unlock()
+	p.updatePong(ln, nil)
+
 	// TODO the raknet library sadly strictly couples the listener and accepted connections,
 	// make sure we first send players a disconnect packet before closing the listener
-	defer ln.Close()
+	defer func() { _ = ln.Close() }()
 	go func() { <-stop; _ = ln.Close() }()
 
-	p.log.Info("Listening for connections", "addr", addr)
+	p.event.Fire(&ReadyEvent{})
+
+	defer p.log.Info("Stopped listening for new connections")
+	p.log.Info("Listening for connections", "addr", ln.Addr().String())
+
 	for {
 		conn, err := ln.Accept()
 		if err != nil {
-			// TODO
+			select {
+			case <-stop:
+				return nil // Listener was closed
+			default:
+			}
 			return fmt.Errorf("error accepting new connection: %w", err)
 		}
 		go p.handleRawConn(conn)
 	}
 }
 
+// updatePong sets the pong data of the listener to reply to an unconnected
+// ping of remoteAddr with. The remoteAddr is nil for the initial pong.
+func (p *Proxy) updatePong(ln *raknet.Listener, remoteAddr net.Addr) {
+	pong := p.newPong(ln.ID(), ln.Addr())
+	if remoteAddr != nil {
+		if p.config.Status.LogPingRequests {
+			p.log.Info("ping request", "remoteAddr", remoteAddr)
+		}
+		e := &PingEvent{remoteAddr: remoteAddr, pong: pong}
+		p.event.Fire(e)
+		if e.Pong() == nil {
+			return // keep the previous pong
+		}
+		pong = e.Pong()
+	}
+	data, err := pong.MarshalText()
+	if err != nil {
+		p.log.Error(err, "error marshaling pong")
+		return
+	}
+	if len(data) > math.MaxInt16 {
+		p.log.Info("pong is too long, keeping the previous one", "length", len(data))
+		return
+	}
+	ln.PongData(data)
+}
+
 func (p *Proxy) handleRawConn(raw net.Conn) {
-	defer raw.Close()
+	conn := newMinecraftConn(raw, p, true)
+	p.muC.Lock()
+	if p.conns == nil {
+		p.conns = map[*minecraftConn]struct{}{}
+	}
+	p.conns[conn] = struct{}{}
+	p.muC.Unlock()
+	defer func() {
+		p.muC.Lock()
+		delete(p.conns, conn)
+		p.muC.Unlock()
+	}()
+
+	conn.log.V(1).Info("accepted connection")
+	conn.setSessionHandler(newLoginSessionHandler(conn))
+	conn.readLoop()
 }
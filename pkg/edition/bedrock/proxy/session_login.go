@@ -1,4 +1,208 @@
 package proxy
 
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+
+	"go.minekube.com/gate/pkg/edition/bedrock/proto"
+	"go.minekube.com/gate/pkg/edition/bedrock/proto/login"
+	bpacket "go.minekube.com/gate/pkg/edition/bedrock/proto/packet"
+	protoutil "go.minekube.com/gate/pkg/gate/proto"
+)
+
+// MinimumProtocol is the oldest supported protocol version (1.19.30)
+// of Bedrock edition clients, the first to request the network settings.
+const MinimumProtocol = 554
+
+type loginState int
+
+const (
+	awaitingNetworkSettingsLoginState loginState = iota
+	awaitingLoginLoginState
+	awaitingHandshakeLoginState
+	loggedInLoginState
+)
+
+// loginSessionHandler performs the login of a Bedrock edition client:
+//  1. RequestNetworkSettings -> NetworkSettings, enables compression
+//  2. Login (verifies the chain) -> ServerToClientHandshake, enables encryption
+//  3. ClientToServerHandshake -> PlayStatus login success
 type loginSessionHandler struct {
+	conn     *minecraftConn
+	state    loginState
+	protocol int32
+	req      *login.Request
+	player   *Player // set once registered
+}
+
+func newLoginSessionHandler(conn *minecraftConn) *loginSessionHandler {
+	return &loginSessionHandler{conn: conn}
+}
+
+const (
+	invalidLoginMsg     = "Invalid login, please retry."
+	xboxLiveRequiredMsg = "You must be logged into Xbox Live to join this server."
+	alreadyConnectedMsg = "You are already connected to this proxy!"
+	joinNotSupportedMsg = "Bedrock edition players can not join this server yet."
+)
+
+func (l *loginSessionHandler) handlePacket(pc *protoutil.PacketContext) {
+	var err error
+	switch l.state {
+	case awaitingNetworkSettingsLoginState:
+		err = l.handleRequestNetworkSettings(pc)
+	case awaitingLoginLoginState:
+		err = l.handleLogin(pc)
+	case awaitingHandshakeLoginState:
+		err = l.handleHandshake(pc)
+	default:
+		l.conn.log.V(1).Info("unexpected packet after login", "id", pc.PacketID)
+		return
+	}
+	if err != nil {
+		l.conn.log.V(1).Info("error during login, closing connection", "err", err)
+		_ = l.conn.close()
+	}
+}
+
+func (l *loginSessionHandler) handleRequestNetworkSettings(pc *protoutil.PacketContext) error {
+	req := new(bpacket.RequestNetworkSettings)
+	if err := proto.UnmarshalPacket(pc.Payload, req); err != nil {
+		return err
+	}
+	if req.ClientProtocol < MinimumProtocol {
+		_ = l.conn.writePacket(&packet.PlayStatus{Status: packet.PlayStatusLoginFailedClient})
+		return fmt.Errorf("unsupported protocol version %d", req.ClientProtocol)
+	}
+	l.protocol = req.ClientProtocol
+
+	cfg := l.conn.config()
+	if err := l.conn.writePacket(&bpacket.NetworkSettings{
+		CompressionThreshold: uint16(cfg.Compression.Threshold),
+		CompressionAlgorithm: bpacket.FlateCompression,
+	}); err != nil {
+		return err
+	}
+	// All following batches are compressed
+	prefixed := l.protocol >= proto.CompressionPrefixProtocol
+	l.conn.encoder.EnableCompression(cfg.Compression.Level, prefixed)
+	l.conn.decoder.EnableCompression(prefixed)
+	l.state = awaitingLoginLoginState
+	return nil
+}
+
+func (l *loginSessionHandler) handleLogin(pc *protoutil.PacketContext) error {
+	pk := new(packet.Login)
+	if err := proto.UnmarshalPacket(pc.Payload, pk); err != nil {
+		return err
+	}
+	if pk.ClientProtocol != l.protocol {
+		return fmt.Errorf("login protocol %d differs from requested %d", pk.ClientProtocol, l.protocol)
+	}
+
+	req, err := login.Parse(pk.ConnectionRequest, login.RootPublicKey)
+	if err != nil {
+		l.conn.disconnect(invalidLoginMsg)
+		return fmt.Errorf("error verifying login: %w", err)
+	}
+	if l.conn.config().OnlineMode && !req.Authenticated {
+		l.conn.log.Info("disconnect player not logged into Xbox Live", "name", req.Identity.DisplayName)
+		l.conn.disconnect(xboxLiveRequiredMsg)
+		return nil
+	}
+	l.req = req
+
+	// Key exchange
+	salt := make([]byte, 16)
+	if _, err = rand.Read(salt); err != nil {
+		return err
+	}
+	token, err := login.Sign(l.conn.proxy.listenerKey, map[string]string{
+		"salt": base64.StdEncoding.EncodeToString(salt),
+	})
+	if err != nil {
+		return fmt.Errorf("error signing handshake: %w", err)
+	}
+	key, err := l.sharedKey(salt)
+	if err != nil {
+		l.conn.disconnect(invalidLoginMsg)
+		return fmt.Errorf("error deriving encryption key: %w", err)
+	}
+	if err = l.conn.writePacket(&packet.ServerToClientHandshake{JWT: []byte(token)}); err != nil {
+		return err
+	}
+	// All following batches are encrypted
+	l.conn.encoder.EnableEncryption(key)
+	l.conn.decoder.EnableEncryption(key)
+	l.state = awaitingHandshakeLoginState
+	return nil
+}
+
+// sharedKey returns the encryption key derived from the salt and the
+// ECDH shared secret of the proxy's listener key and the client's key.
+func (l *loginSessionHandler) sharedKey(salt []byte) ([32]byte, error) {
+	priv, err := l.conn.proxy.listenerKey.ECDH()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pub, err := l.req.PublicKey.ECDH()
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("invalid client key: %w", err)
+	}
+	secret, err := priv.ECDH(pub)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(append(salt, secret...)), nil
+}
+
+func (l *loginSessionHandler) handleHandshake(pc *protoutil.PacketContext) error {
+	if err := proto.UnmarshalPacket(pc.Payload, new(packet.ClientToServerHandshake)); err != nil {
+		return err
+	}
+	l.state = loggedInLoginState
+
+	player, err := newPlayer(l.conn, l.protocol, l.req)
+	if err != nil {
+		return err
+	}
+	p := l.conn.proxy
+	e := &LoginEvent{player: player}
+	p.event.Fire(e)
+	if l.conn.isClosed() {
+		return nil
+	}
+	if !e.Allowed() {
+		player.Disconnect(e.Reason())
+		return nil
+	}
+	if err = l.conn.writePacket(&packet.PlayStatus{Status: packet.PlayStatusLoginSuccess}); err != nil {
+		return err
+	}
+	if !p.registerPlayer(player) {
+		player.Disconnect(alreadyConnectedMsg)
+		return nil
+	}
+	l.player = player
+	p.log.Info("Bedrock player has connected", "player", player, "id", player.ID(),
+		"xuid", player.XUID(), "version", player.GameVersion())
+	p.event.Fire(&PostLoginEvent{player: player})
+
+	// TODO connect the player to a server
+	player.Disconnect(joinNotSupportedMsg)
+	return nil
+}
+
+func (l *loginSessionHandler) disconnected() {
+	if l.player == nil {
+		return
+	}
+	if l.conn.proxy.unregisterPlayer(l.player) {
+		l.conn.proxy.log.Info("Bedrock player has disconnected", "player", l.player)
+		l.conn.proxy.event.Fire(&DisconnectEvent{player: l.player})
+	}
 }
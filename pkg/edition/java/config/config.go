@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 
 	"go.minekube.com/gate/pkg/util/validation"
 )
@@ -21,6 +22,11 @@ var DefaultConfig = Config{
 		// Contains Gate's icon
 		Favicon:         "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAEAAAABACAYAAACqaXHeAAAABGdBTUEAALGPC/xhBQAAACBjSFJNAAB6JgAAgIQAAPoAAACA6AAAdTAAAOpgAAA6mAAAF3CculE8AAAABmJLR0QA/wD/AP+gvaeTAAAACXBIWXMAAAsTAAALEwEAmpwYAAAAB3RJTUUH5AgJCgs6JBZy0AAAB+lJREFUeNrtmGuMXVUZht/LOvcz7diWklbJaKGCWKXFUiiBQjAEhRSLSCEqIKAiogRTmiriBeSiUUhjMCZe2qQxNE0EUZSCidAgYMBSLBJCDC1taAg4xaq1cz/788feZzqgocCoP2Q/f87JOXvvfOtd73dZGygpKSkpKSkpKSkpKSkpKSkpKSkpKXnzwNd7w+ULD0NEBtmQBFqQBNuICFRYwzc3bf7/E+Cyo+cgAIiEbESWJdl1WSQ5VGvWRwnk/0XgpnvfmAhrv3h+HhgFFeJCBCLw8Wt//B8XIB3ogs8umJMHAOCQvtnYtfP5eRGxVNaxMmdKgqzdWSfbIuuuocHBLa2ednx16WJcd9fvXn9EAQSQyGgBwUCMMbAvAvHfcIAOaBHnl0REY9fO56+itFHSjbI/JHuxrMWyl8r6mq27m+3WKpJ1kvjG2UtevyUtyDqK0t2UNklaLalu63+fAp9bNBdZFogsq0j6OsVVkix7L8WNkh6VLVuLlXyapKbsMUkrR4aGV9caNbRnTkWKPC0kgdpv7e7n2GgH7ant8WgiYomoX8uqUXoIwKm1Rn0wJQMAGu0mBv8xhEZPAxIhOX9W8byR4VHUGjUcf86qyaVApVrB6MgYIC4leaUsS/oLySsprQcwBgRkVW1fIfsGWVVJn29Naf8CwPYUedAjQ8OsNxuHApiHwAwAIwB2AtjaaNX/CgQAiuQM27NIhixQqqaU+mTtA9AfEUMA0JzSRERMB3BUIPoCgQjsiIg/NHuaewDg0TtvxqJlK964A6447nAE0CLwM0mnygbFGzujY19WMhD5bjgZTu6hdLekE4qduDAi1jWntIEseimuIHmBrLfJVuGAAdmbZV1t6yGnNI3kBkkLaE2zRNnDsnbLGiR1EcUHK5WKlbyc5BdkvUdSPXeAB21tln3D6PDIvdV6DQBeVYRXdYDyvHsXyYW5dd1PYoNURafTwS2/fRIAsPKU+eg96C17EVhNcavskPgCQCDCtK4RuaLY0WdlPWW7T9a7JS+RdYukpbJHip3PcoHctXUmKyMZkuBK+jTJb8tqSeqn9ICthuyFsk4kubZar50P4DeTSgHZAHAEyd6i7z9LYgcA9M6chuuXnwzLoPLWiIjbKd7ezfV8B+JIkp+gVNzPj0jaKvsQ2xtkLZI1n+TRo8Mj99QatY85+WRJ62TXJW0leb6kfZ2xzp/rzcZ8ktcUi98B4hJZD1KqyLqU5E0AZgH4EoDfA/j7GxbAuc0PpshCgJcoDiHGOxIDMZfgdACh5InFbRuJflA1kffJNsVNtXptS7GzOyJii6RFsqqkZjsZ1XqtX/aLJLPiOcOSnsuybLA1tQ2S51CcXQi/RvZ9TgaBEZA/BHAugEUAjgMwH8ADkxAgARGaULlJERGAJESWWdK1kpZJHJMUyncaFD+TZdltTumxl17oP3f2nEN6Jc0DeSmIWQAato/tVm5KjbzwVos5iONiklSlVoHtOsljKMFWJvswWSsmtHMCqBffWwCOnJwANgKxp7soWdNJNYAYcTKyTLBlSklWWK7ISnnQqgDAvMXz4pmt2z4gcRWlYwrrTsxvOC+uRBSuA0AS+8UhUqUC2XWK04tYJOmCA6R476RqgJMRgW0SB2Q1Zb+dZB+JJyQByDqUrpP0fVGZk1fSOsO5AJCNbX/cfoKsNZJmSRqguE7SJol7bH9K1umyUaz/XwSgVIzfgpMySmOSQHIUwG1FK504JXVQ9NQD7f5rLILxlKRtebvxQbLOlvWELIyOjIbkJ11JqNaqMxAxOz8cGRLzRUgflTSrsPIaklcC6NgJqZJOG0+viQIExgsrRZAqnuUBWbuKHBeAXwL46cSeHnnezwCQAXh6UqNwqiTUW80XndJ6O3X7/WVO6RxSmjKtF+3eNqq16hSSVyt5vu3udXjh2V1w8ludjPz3tKNSq3ZaU3tQrVdnyn6fnf+n4h6Nf0/dexq2VKlVIWsMwKauQQGcPSHnEcA7AawHcA+ANQAOnpQDsixDZBlk/Uj2SbZOk3WQ5B/IOhOIxyNQp3iKpJOK9naErDpJ9B15GGxvL4oWZF+i5L0A/kZruaT3jhc6KSGimwIDpMaK8fZwSStJPgLgfgB3ALgAwEIAZwEYALARQA+ACwEcUYR/RwB/4mTOAgBw43nvR6okSJoj61uyz7RV3T/Pu7uAp2ytln2LrDapiyWudUoLZK2XfbgnnAEo7bb9sKwzC6tfjyy+Um81EMAMST+XdXxeawAAzwM4EcB2AMcDuBXAgn8T8kjhgpUA+ic1CeaFMN89kNudfBGlU2V9UPZcWQ1JeyU9RvInTmk3xdmSWpSeQAC2Hpd9nqxPyjpKsmTtJLlByS+KfFqSKW4OZHAlgcBuAJdTugTAoUWcOwDsLcJ6GMAyAMsLUWYCGAWwragLGwtnYNIOAICbLz4DKe0/0R13+hI8fv+jDVlJ0miWZUOykZLHT3sUQRAUUa3X8OGrvotf3bqyRYlOaSCyLJvoIjIPpd5uoG/uO/DcMzu743i1iHOsk3U6BMffPnXPbEUdyCJigOTL3htM6jD0Sr53+VmQ07gQ432aQiBQq9cAomhdQgBo9bQg7x+eim6AyDJUm00gAikRlLCvswc9noZqT6uozvGyELvvRI5ddhUeufM7ebcgX/k+BXwNCy8pKSkpKSkpKSkpKSkpKSkpKSkpKXkz8k8RHxEbZN/8lgAAACV0RVh0ZGF0ZTpjcmVhdGUAMjAyMC0wOC0wOVQxMDoxMTo0MyswMDowMN6nNEYAAAAldEVYdGRhdGU6bW9kaWZ5ADIwMjAtMDgtMDlUMTA6MTE6NDMrMDA6MDCv+oz6AAAAAElFTkSuQmCC",
 		LogPingRequests: false,
+		PingPassthrough: PingPassthrough{
+			Mode:     DisabledPingPassthroughMode,
+			Timeout:  2000,
+			CacheTTL: 5000,
+		},
 	},
 	Query: Query{
 		Enabled:     false,
@@ -28,12 +34,24 @@ var DefaultConfig = Config{
 		ShowPlugins: false,
 	},
 	AnnounceForge:                        false,
-	Servers:                              map[string]string{},
+	Servers:                              map[string]Server{},
 	Try:                                  []string{},
 	ForcedHosts:                          map[string][]string{},
 	FailoverOnUnexpectedServerDisconnect: true,
 	ConnectionTimeout:                    5000,
 	ReadTimeout:                          30000,
+	HealthCheck: HealthCheck{
+		Enabled:  false,
+		Interval: 10000,
+		Timeout:  3000,
+	},
+	TabList: TabList{
+		Enabled:         false,
+		Header:          "§bA Gate Proxy\n§7{online} players online",
+		Footer:          "§7{server} §8| §7{ping}ms",
+		RefreshInterval: 1000,
+		Policy:          OverrideTabListPolicy,
+	},
 	Quota: Quota{
 		Connections: QuotaSettings{
 			Enabled:    true,
@@ -53,6 +71,7 @@ var DefaultConfig = Config{
 		Level:     -1,
 	},
 	HAProxyProtocol:                     false,
+	HAProxyTrustedCIDRs:                 []string{},
 	ShouldPreventClientProxyConnections: false,
 	BungeePluginChannelEnabled:          true,
 	BuiltinCommands:                     true,
@@ -77,18 +96,21 @@ type Config struct {
 	// Forge/FML-compatible server. By default, this is disabled.
 	AnnounceForge bool
 
-	Servers                              map[string]string // name:address
+	Servers                              map[string]Server // name:server
 	Try                                  []string          // Try server names order
 	ForcedHosts                          ForcedHosts
 	FailoverOnUnexpectedServerDisconnect bool
+	HealthCheck                          HealthCheck
+	TabList                              TabList
 
 	ConnectionTimeout int // Write timeout
 	ReadTimeout       int
 
 	Quota                               Quota
 	Compression                         Compression
-	HAProxyProtocol                     bool // Enable HA-Proxy protocol mode
-	ShouldPreventClientProxyConnections bool // Sends player IP to Mojang on login
+	HAProxyProtocol                     bool     // Enable HA-Proxy protocol mode
+	HAProxyTrustedCIDRs                 []string // Upstreams allowed to send PROXY headers, empty trusts all
+	ShouldPreventClientProxyConnections bool     // Sends player IP to Mojang on login
 
 	BungeePluginChannelEnabled       bool
 	BuiltinCommands                  bool
@@ -101,12 +123,36 @@ type Config struct {
 }
 
 type (
-	ForcedHosts map[string][]string // virtualhost:server names
+	ForcedHosts map[string][]string // virtualhost pattern:server names
 	Status      struct {
 		ShowMaxPlayers  int
 		Motd            string
+		ForcedHostMotds map[string]string // virtualhost:motd, overrides Motd
 		Favicon         string
 		LogPingRequests bool
+		PingPassthrough PingPassthrough
+	}
+	// PingPassthrough relays server list pings of backend servers.
+	PingPassthrough struct {
+		Mode     PingPassthroughMode
+		Timeout  int // Milliseconds to wait for a backend server ping response
+		CacheTTL int // Milliseconds to cache backend server ping responses
+	}
+	// HealthCheck periodically pings the backend servers
+	// to not send players to offline servers.
+	HealthCheck struct {
+		Enabled  bool
+		Interval int // Milliseconds between pings of a server
+		Timeout  int // Milliseconds to wait for a ping response before considering the server offline
+	}
+	// TabList renders the tab list header and footer of
+	// players from templates and refreshes them periodically.
+	TabList struct {
+		Enabled         bool
+		Header          string        // Template in legacy '§' format or text component json
+		Footer          string        // Template in legacy '§' format or text component json
+		RefreshInterval int           // Milliseconds between re-rendering the templates
+		Policy          TabListPolicy // How to handle the header and footer sent by backend servers
 	}
 	Query struct {
 		Enabled     bool
@@ -114,8 +160,9 @@ type (
 		ShowPlugins bool
 	}
 	Forwarding struct {
-		Mode           ForwardingMode
-		VelocitySecret string // Used with "velocity" mode
+		Mode             ForwardingMode
+		VelocitySecret   string // Used with "velocity" mode
+		BungeeGuardToken string // Used with "bungeeguard" mode
 	}
 	Compression struct {
 		Threshold int
@@ -144,6 +191,45 @@ const (
 	// VelocityForwardingMode is a forwarding mode specified by the Velocity java proxy and
 	// supported by PaperSpigot for versions starting at 1.13.
 	VelocityForwardingMode ForwardingMode = "velocity"
+	// BungeeGuardForwardingMode is the legacy forwarding mode with an additional
+	// "bungeeguard-token" profile property authenticating the proxy to backend
+	// servers running the BungeeGuard plugin.
+	BungeeGuardForwardingMode ForwardingMode = "bungeeguard"
+)
+
+// Legacy returns true if the mode forwards player info
+// in the handshake address like BungeeCord does.
+func (m ForwardingMode) Legacy() bool {
+	return m == LegacyForwardingMode || m == BungeeGuardForwardingMode
+}
+
+// PingPassthroughMode is the mode for relaying backend server pings.
+type PingPassthroughMode string
+
+const (
+	// DisabledPingPassthroughMode only uses the status config for server list pings.
+	DisabledPingPassthroughMode PingPassthroughMode = "disabled"
+	// DescriptionPingPassthroughMode uses the description and favicon of the backend server.
+	DescriptionPingPassthroughMode PingPassthroughMode = "description"
+	// AllPingPassthroughMode relays the whole ping response of the backend server,
+	// including version, player counts and sample.
+	AllPingPassthroughMode PingPassthroughMode = "all"
+)
+
+// TabListPolicy is the policy for the tab list header and
+// footer sent by backend servers if the TabList is enabled.
+type TabListPolicy string
+
+const (
+	// OverrideTabListPolicy drops the header and footer sent by
+	// backend servers and only shows the rendered templates.
+	OverrideTabListPolicy TabListPolicy = "override"
+	// MergeTabListPolicy shows the header and footer sent by backend
+	// servers below the rendered header and above the rendered footer.
+	MergeTabListPolicy TabListPolicy = "merge"
+	// PassthroughTabListPolicy forwards the header and footer sent
+	// by backend servers and does not render the templates.
+	PassthroughTabListPolicy TabListPolicy = "passthrough"
 )
 
 // Validate validates Config.
@@ -155,8 +241,13 @@ func (c *Config) Validate() (warns []error, errs []error) {
 		e("config must not be nil")
 		return
 	}
-	if c.HAProxyProtocol {
-		e("Proxy protocol is not supported yet, disable it")
+	for _, cidr := range c.HAProxyTrustedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			e("Invalid HA-Proxy trusted CIDR %q: %v", cidr, err)
+		}
+	}
+	if !c.HAProxyProtocol && len(c.HAProxyTrustedCIDRs) != 0 {
+		w("HA-Proxy trusted CIDRs are ignored since HA-Proxy protocol is disabled.")
 	}
 
 	if len(c.Bind) == 0 {
@@ -175,22 +266,48 @@ func (c *Config) Validate() (warns []error, errs []error) {
 	case NoneForwardingMode:
 		w("Player forwarding is disabled! Backend servers will have players with " +
 			"offline-mode UUIDs and the same IP as the proxy.")
-	case LegacyForwardingMode, VelocityForwardingMode:
+	case LegacyForwardingMode, VelocityForwardingMode, BungeeGuardForwardingMode:
 	default:
-		e("Unknown forwarding mode %q, must be one of none,legacy,velocity", c.Forwarding.Mode)
+		e("Unknown forwarding mode %q, must be one of none,legacy,velocity,bungeeguard", c.Forwarding.Mode)
 	}
 
 	if len(c.Servers) == 0 {
 		w("No backend servers configured.")
 	}
 
-	for name, addr := range c.Servers {
+	for name, server := range c.Servers {
 		if !validation.ValidServerName(name) {
 			e("Invalid server name format %q: %s and length be 1-%d", name,
 				validation.QualifiedNameErrMsg, validation.QualifiedNameMaxLength)
 		}
-		if err := validation.ValidHostPort(addr); err != nil {
-			e("Invalid address %q for server %q: %w", addr, name, err)
+		if err := validation.ValidHostPort(server.Address); err != nil {
+			e("Invalid address %q for server %q: %w", server.Address, name, err)
+		}
+		switch server.Forwarding.Mode {
+		case "", NoneForwardingMode, LegacyForwardingMode, VelocityForwardingMode, BungeeGuardForwardingMode:
+		default:
+			e("Unknown forwarding mode %q for server %q, must be one of none,legacy,velocity,bungeeguard",
+				server.Forwarding.Mode, name)
+		}
+		if server.ConnectionTimeout < 0 {
+			e("Invalid connection timeout %d for server %q, use a number >= 0", server.ConnectionTimeout, name)
+		}
+		if server.ReadTimeout < 0 {
+			e("Invalid read timeout %d for server %q, use a number >= 0", server.ReadTimeout, name)
+		}
+		switch server.TabListPolicy {
+		case "", OverrideTabListPolicy, MergeTabListPolicy, PassthroughTabListPolicy:
+		default:
+			e("Unknown tab list policy %q for server %q, must be one of override,merge,passthrough",
+				server.TabListPolicy, name)
+		}
+		switch f := c.ForwardingFor(name); {
+		case f.Mode == BungeeGuardForwardingMode && f.BungeeGuardToken == "":
+			e("No BungeeGuard token configured for server %q, "+
+				"the server will reject the forwarded player info.", name)
+		case f.Mode == VelocityForwardingMode && f.VelocitySecret == "":
+			w("No velocity secret configured for server %q, "+
+				"the server will reject the forwarded player info.", name)
 		}
 	}
 
@@ -201,9 +318,61 @@ func (c *Config) Validate() (warns []error, errs []error) {
 	}
 
 	for host, servers := range c.ForcedHosts {
+		if err := validForcedHostPattern(host); err != nil {
+			e("Invalid forced host %q: %v", host, err)
+		}
+		if len(servers) == 0 {
+			w("Forced host %q has no servers and is ignored", host)
+		}
 		for _, name := range servers {
-			e("Forced host %q server %q must be registered under servers", host, name)
+			if _, ok := c.Servers[name]; !ok {
+				e("Forced host %q server %q must be registered under servers", host, name)
+			}
+		}
+	}
+
+	for host := range c.Status.ForcedHostMotds {
+		if err := validForcedHostPattern(host); err != nil {
+			e("Invalid forced host %q of status motd: %v", host, err)
+		}
+	}
+
+	switch c.Status.PingPassthrough.Mode {
+	case DisabledPingPassthroughMode, DescriptionPingPassthroughMode, AllPingPassthroughMode:
+	default:
+		e("Unknown ping passthrough mode %q, must be one of disabled,description,all", c.Status.PingPassthrough.Mode)
+	}
+	if c.Status.PingPassthrough.Mode != DisabledPingPassthroughMode {
+		if c.Status.PingPassthrough.Timeout <= 0 {
+			e("Invalid ping passthrough timeout %d, use a number > 0", c.Status.PingPassthrough.Timeout)
 		}
+		if c.Status.PingPassthrough.CacheTTL < 0 {
+			e("Invalid ping passthrough cache TTL %d, use a number >= 0", c.Status.PingPassthrough.CacheTTL)
+		}
+	}
+
+	if c.HealthCheck.Enabled {
+		if c.HealthCheck.Interval <= 0 {
+			e("Invalid health check interval %d, use a number > 0", c.HealthCheck.Interval)
+		}
+		if c.HealthCheck.Timeout <= 0 {
+			e("Invalid health check timeout %d, use a number > 0", c.HealthCheck.Timeout)
+		}
+	}
+
+	if c.TabList.Enabled {
+		if c.TabList.RefreshInterval <= 0 {
+			e("Invalid tab list refresh interval %d, use a number > 0", c.TabList.RefreshInterval)
+		}
+		switch c.TabList.Policy {
+		case OverrideTabListPolicy, MergeTabListPolicy, PassthroughTabListPolicy:
+		default:
+			e("Unknown tab list policy %q, must be one of override,merge,passthrough", c.TabList.Policy)
+		}
+	}
+
+	if c.Query.Enabled && (c.Query.Port < 1 || c.Query.Port > 65535) {
+		e("Invalid query port %d: must be 1..65535", c.Query.Port)
 	}
 
 	if c.Compression.Level < -1 || c.Compression.Level > 9 {
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Servers returns the names of the servers of the forced host matching
+// the virtual host a client connected with or nil if no forced host matches.
+// See MatchForcedHost for how hosts are matched.
+func (f ForcedHosts) Servers(virtualHost string) []string {
+	servers, _ := MatchForcedHost(f, virtualHost)
+	return servers
+}
+
+// MatchForcedHost returns the value of the forced host pattern in hosts matching the
+// virtual host a client connected with. The port and any Forge marker of the virtual
+// host are ignored and hosts are compared case-insensitively.
+//
+// Patterns are either exact hosts like "lobby.example.com", wildcards like "*.example.com"
+// matching all subdomains or "*" matching any host. An exact host is preferred over
+// wildcards and a longer wildcard is preferred over a shorter one.
+func MatchForcedHost[V any](hosts map[string]V, virtualHost string) (v V, ok bool) {
+	host := normalizeHost(virtualHost)
+	if host == "" {
+		return v, false
+	}
+	bestLen := -1
+	for pattern, value := range hosts {
+		pattern = normalizeHost(pattern)
+		if pattern == host {
+			return value, true // exact match
+		}
+		if len(pattern) > bestLen && matchWildcard(pattern, host) {
+			v, ok, bestLen = value, true, len(pattern)
+		}
+	}
+	return v, ok
+}
+
+func matchWildcard(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	suffix := strings.TrimPrefix(pattern, "*")
+	return len(suffix) != len(pattern) && // is wildcard
+		len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+// normalizeHost strips the port, Forge marker and trailing dot of a host and lowercases it.
+func normalizeHost(host string) string {
+	// Forge and others append data separated by a null byte
+	if i := strings.IndexByte(host, 0); i != -1 {
+		host = host[:i]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func validForcedHostPattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if pattern == "*" {
+		return nil
+	}
+	if strings.Contains(strings.TrimPrefix(pattern, "*."), "*") {
+		return fmt.Errorf(`wildcard must be "*" or a "*." prefix like "*.example.com"`)
+	}
+	return nil
+}
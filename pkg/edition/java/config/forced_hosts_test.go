@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForcedHosts_Servers(t *testing.T) {
+	hosts := ForcedHosts{
+		"lobby.example.com":      {"lobby"},
+		"*.example.com":          {"fallback"},
+		"*.survival.example.com": {"survival"},
+		"Creative.Example.com":   {"creative"},
+	}
+	for virtualHost, want := range map[string][]string{
+		"lobby.example.com:25565":            {"lobby"},
+		"LOBBY.example.com.:25565":           {"lobby"},
+		"lobby.example.com\x00FML\x00:25565": {"lobby"},
+		"creative.example.com:25565":         {"creative"},
+		"pvp.example.com:25565":              {"fallback"},
+		"eu.survival.example.com:25565":      {"survival"},
+		"survival.example.com:25565":         {"fallback"},
+		"example.com:25565":                  nil,
+		"other.net:25565":                    nil,
+		"":                                   nil,
+	} {
+		require.Equal(t, want, hosts.Servers(virtualHost), virtualHost)
+	}
+}
+
+func TestMatchForcedHost_catchAll(t *testing.T) {
+	motds := map[string]string{
+		"*":                 "any",
+		"lobby.example.com": "lobby",
+	}
+	motd, ok := MatchForcedHost(motds, "other.net:25565")
+	require.True(t, ok)
+	require.Equal(t, "any", motd)
+
+	motd, ok = MatchForcedHost(motds, "lobby.example.com:25565")
+	require.True(t, ok)
+	require.Equal(t, "lobby", motd)
+}
+
+func TestConfig_Validate_forcedHosts(t *testing.T) {
+	c := DefaultConfig
+	c.Servers = map[string]Server{"lobby": {Address: "localhost:25566"}}
+	c.ForcedHosts = ForcedHosts{
+		"lobby.example.com": {"lobby"},
+		"*.example.com":     {"lobby"},
+	}
+	_, errs := c.Validate()
+	require.Empty(t, errs)
+
+	c.ForcedHosts = ForcedHosts{
+		"lobby.example.com": {"unknown"},
+		"lobby.*.com":       {"lobby"},
+	}
+	_, errs = c.Validate()
+	require.Len(t, errs, 2)
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Server is a backend server registered with the proxy on startup.
+//
+// It can be configured as plain address string or as object with
+// the address and options overriding the proxy's config for the server.
+type Server struct {
+	Address           string
+	Forwarding        ServerForwarding
+	ConnectionTimeout int           // Overrides Config.ConnectionTimeout if > 0
+	ReadTimeout       int           // Overrides Config.ReadTimeout if > 0
+	TabListPolicy     TabListPolicy // Overrides TabList.Policy if set
+	// Sends a PROXY protocol v2 header with the real player address when connecting,
+	// e.g. for Paper's `proxy-protocol` setting. Independent of the forwarding mode.
+	ProxyProtocol bool
+}
+
+// ServerForwarding overrides the proxy's player info forwarding settings for a server.
+// Empty fields use the proxy's settings.
+type ServerForwarding struct {
+	Mode             ForwardingMode
+	VelocitySecret   string // Used with "velocity" mode
+	BungeeGuardToken string // Used with "bungeeguard" mode
+}
+
+// UnmarshalText unmarshals a server from its plain address.
+func (s *Server) UnmarshalText(text []byte) error {
+	*s = Server{Address: string(text)}
+	return nil
+}
+
+// UnmarshalJSON unmarshals a server from its plain address or a server object.
+func (s *Server) UnmarshalJSON(data []byte) error {
+	var addr string
+	if json.Unmarshal(data, &addr) == nil {
+		return s.UnmarshalText([]byte(addr))
+	}
+	type server Server // prevent recursion
+	return json.Unmarshal(data, (*server)(s))
+}
+
+// server returns the server with the given name, compared case-insensitively.
+func (c *Config) server(name string) (Server, bool) {
+	if s, ok := c.Servers[name]; ok {
+		return s, true
+	}
+	for n, s := range c.Servers {
+		if strings.EqualFold(n, name) {
+			return s, true
+		}
+	}
+	return Server{}, false
+}
+
+// ForwardingFor returns the player info forwarding settings to use for the server with the given name.
+func (c *Config) ForwardingFor(server string) Forwarding {
+	f := c.Forwarding
+	s, ok := c.server(server)
+	if !ok {
+		return f
+	}
+	if s.Forwarding.Mode != "" {
+		f.Mode = s.Forwarding.Mode
+	}
+	if s.Forwarding.VelocitySecret != "" {
+		f.VelocitySecret = s.Forwarding.VelocitySecret
+	}
+	if s.Forwarding.BungeeGuardToken != "" {
+		f.BungeeGuardToken = s.Forwarding.BungeeGuardToken
+	}
+	return f
+}
+
+// ConnectionTimeoutFor returns the connection timeout
+// in milliseconds to use for the server with the given name.
+func (c *Config) ConnectionTimeoutFor(server string) int {
+	if s, ok := c.server(server); ok && s.ConnectionTimeout > 0 {
+		return s.ConnectionTimeout
+	}
+	return c.ConnectionTimeout
+}
+
+// ReadTimeoutFor returns the read timeout in
+// milliseconds to use for the server with the given name.
+func (c *Config) ReadTimeoutFor(server string) int {
+	if s, ok := c.server(server); ok && s.ReadTimeout > 0 {
+		return s.ReadTimeout
+	}
+	return c.ReadTimeout
+}
+
+// TabListPolicyFor returns the tab list policy to use for the server with the given name.
+func (c *Config) TabListPolicyFor(server string) TabListPolicy {
+	if s, ok := c.server(server); ok && s.TabListPolicy != "" {
+		return s.TabListPolicy
+	}
+	return c.TabList.Policy
+}
+
+// ProxyProtocolFor returns true if the server with the given
+// name is configured to receive a PROXY protocol header.
+func (c *Config) ProxyProtocolFor(server string) bool {
+	s, ok := c.server(server)
+	return ok && s.ProxyProtocol
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_decode(t *testing.T) {
+	input := map[string]any{
+		"servers": map[string]any{
+			"lobby": "localhost:25566",
+			"legacy": map[string]any{
+				"address":     "localhost:25567",
+				"forwarding":  map[string]any{"mode": "legacy"},
+				"readTimeout": 60000,
+			},
+		},
+	}
+	var c Config
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.TextUnmarshallerHookFunc(),
+		Result:     &c,
+	})
+	require.NoError(t, err)
+	require.NoError(t, dec.Decode(input))
+
+	require.Equal(t, map[string]Server{
+		"lobby": {Address: "localhost:25566"},
+		"legacy": {
+			Address:     "localhost:25567",
+			Forwarding:  ServerForwarding{Mode: LegacyForwardingMode},
+			ReadTimeout: 60000,
+		},
+	}, c.Servers)
+}
+
+func TestServer_UnmarshalJSON(t *testing.T) {
+	var servers map[string]Server
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"lobby": "localhost:25566",
+		"legacy": {"address": "localhost:25567", "connectionTimeout": 1000}
+	}`), &servers))
+	require.Equal(t, map[string]Server{
+		"lobby":  {Address: "localhost:25566"},
+		"legacy": {Address: "localhost:25567", ConnectionTimeout: 1000},
+	}, servers)
+}
+
+func TestConfig_ForwardingFor(t *testing.T) {
+	c := DefaultConfig
+	c.ReadTimeout = 30000
+	c.Forwarding = Forwarding{
+		Mode:             VelocityForwardingMode,
+		VelocitySecret:   "secret",
+		BungeeGuardToken: "token",
+	}
+	c.Servers = map[string]Server{
+		"modern": {Address: "localhost:25566"},
+		"Legacy": {
+			Address:     "localhost:25567",
+			Forwarding:  ServerForwarding{Mode: BungeeGuardForwardingMode, BungeeGuardToken: "other"},
+			ReadTimeout: 60000,
+		},
+	}
+
+	f := c.ForwardingFor("modern")
+	require.Equal(t, VelocityForwardingMode, f.Mode)
+	require.Equal(t, "secret", f.VelocitySecret)
+	require.Equal(t, 30000, c.ReadTimeoutFor("modern"))
+
+	f = c.ForwardingFor("legacy")
+	require.Equal(t, BungeeGuardForwardingMode, f.Mode)
+	require.Equal(t, "other", f.BungeeGuardToken)
+	require.Equal(t, 60000, c.ReadTimeoutFor("legacy"))
+	require.Equal(t, c.ConnectionTimeout, c.ConnectionTimeoutFor("legacy"))
+}
+
+func TestConfig_TabListPolicyFor(t *testing.T) {
+	c := DefaultConfig
+	c.TabList.Policy = MergeTabListPolicy
+	c.Servers = map[string]Server{
+		"lobby":    {Address: "localhost:25566"},
+		"minigame": {Address: "localhost:25567", TabListPolicy: PassthroughTabListPolicy},
+	}
+	require.Equal(t, MergeTabListPolicy, c.TabListPolicyFor("lobby"))
+	require.Equal(t, PassthroughTabListPolicy, c.TabListPolicyFor("Minigame"))
+	require.Equal(t, MergeTabListPolicy, c.TabListPolicyFor("unknown"))
+}
+
+func TestConfig_Validate_forwardingSecrets(t *testing.T) {
+	c := DefaultConfig
+	c.Servers = map[string]Server{
+		"lobby": {Address: "localhost:25566"},
+		"modern": {
+			Address:    "localhost:25567",
+			Forwarding: ServerForwarding{Mode: VelocityForwardingMode},
+		},
+		"guarded": {
+			Address:    "localhost:25568",
+			Forwarding: ServerForwarding{Mode: BungeeGuardForwardingMode, BungeeGuardToken: "token"},
+		},
+	}
+	warns, errs := c.Validate()
+	require.Empty(t, errs)
+	require.Len(t, warns, 1)
+	require.Contains(t, warns[0].Error(), `"modern"`)
+
+	c.Servers["modern"] = Server{
+		Address:    "localhost:25567",
+		Forwarding: ServerForwarding{Mode: VelocityForwardingMode, VelocitySecret: "secret"},
+	}
+	warns, errs = c.Validate()
+	require.Empty(t, errs)
+	require.Empty(t, warns)
+
+	c.Servers["guarded"] = Server{
+		Address:    "localhost:25568",
+		Forwarding: ServerForwarding{Mode: BungeeGuardForwardingMode},
+	}
+	_, errs = c.Validate()
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `"guarded"`)
+}
+
+func TestConfig_ProxyProtocolFor(t *testing.T) {
+	c := DefaultConfig
+	c.Servers = map[string]Server{
+		"lobby":  {Address: "localhost:25566"},
+		"Shield": {Address: "localhost:25567", ProxyProtocol: true},
+	}
+	require.False(t, c.ProxyProtocolFor("lobby"))
+	require.True(t, c.ProxyProtocolFor("shield"))
+	require.False(t, c.ProxyProtocolFor("unknown"))
+}
@@ -14,6 +14,7 @@ import (
 	"go.minekube.com/gate/pkg/edition/java/proxy/phase"
 	"go.uber.org/atomic"
 
+	"go.minekube.com/gate/pkg/edition/java/proto/codec"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
@@ -189,9 +190,9 @@ func (c *minecraftConn) startReadLoop() {
 
 		// TODO wrap packetCtx into struct with source info
 		// (minecraftConn) and chain into packet interceptor to...
-		//  - packet interception
 		//  - statistics / count bytes
 		//  - in turn call session handler
+		// Play state packets are intercepted by the proxy's session handlers (see proxy.InterceptPacket).
 
 		// Handle packet by connection's session handler.
 		c.SessionHandler().HandlePacket(packetCtx)
@@ -256,6 +257,12 @@ func (c *minecraftConn) BufferPacket(packet proto.Packet) (err error) {
 	}
 	defer func() { c.closeOnErr(err) }()
 	_, err = c.wr.WritePacket(packet)
+	if errors.Is(err, codec.ErrPacketNotRegistered) && c.State() == state.Config {
+		// Play packets can't be sent while the client is in the config state (1.20.2+),
+		// drop them instead of closing the connection.
+		c.log.V(1).Info("dropped packet not available in config state", "packet", fmt.Sprintf("%T", packet))
+		return nil
+	}
 	return err
 }
 
@@ -0,0 +1,59 @@
+package netmc
+
+import (
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	bytesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_received_bytes_total",
+		Help: "Bytes received from Minecraft connections of clients or servers.",
+	}, []string{"conn"})
+	bytesSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_sent_bytes_total",
+		Help: "Bytes sent to Minecraft connections of clients or servers.",
+	}, []string{"conn"})
+	packetsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_received_packets_total",
+		Help: "Packets received from Minecraft connections of clients or servers.",
+	}, []string{"conn"})
+	packetsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_sent_packets_total",
+		Help: "Packets sent to Minecraft connections of clients or servers.",
+	}, []string{"conn"})
+)
+
+// connLabel returns the metrics label of a client or server connection.
+func connLabel(client bool) string {
+	if client {
+		return "client"
+	}
+	return "server"
+}
+
+// countReader counts the bytes read from r.
+type countReader struct {
+	r io.Reader
+	c prometheus.Counter
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.c.Add(float64(n))
+	return n, err
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	c prometheus.Counter
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.c.Add(float64(n))
+	return n, err
+}
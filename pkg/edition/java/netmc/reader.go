@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.minekube.com/gate/pkg/edition/java/proto/codec"
 	"go.minekube.com/gate/pkg/gate/proto"
 	"go.minekube.com/gate/pkg/util/errs"
@@ -30,8 +31,10 @@ var ErrReadPacketRetry = errors.New("error reading packet, retry")
 
 // NewReader returns a new packet reader.
 func NewReader(conn net.Conn, direction proto.Direction, readTimeout time.Duration, log logr.Logger) Reader {
-	readBuf := bufio.NewReader(conn)
+	label := connLabel(direction == proto.ServerBound) // reads from clients are server bound
+	readBuf := bufio.NewReader(&countReader{r: conn, c: bytesReceived.WithLabelValues(label)})
 	return &reader{
+		packets:     packetsReceived.WithLabelValues(label),
 		c:           conn,
 		readTimeout: readTimeout,
 		log:         log.WithName("reader"),
@@ -45,6 +48,7 @@ type reader struct {
 	readTimeout time.Duration
 	c           net.Conn // underlying connection
 	readBuf     *bufio.Reader
+	packets     prometheus.Counter
 	*codec.Decoder
 }
 
@@ -61,6 +65,7 @@ func (r *reader) ReadPacket() (*proto.PacketContext, error) {
 		r.log.V(1).Info("error reading packet, closing connection", "err", err)
 		return nil, err
 	}
+	r.packets.Inc()
 	return packetCtx, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.minekube.com/gate/pkg/edition/java/proto/codec"
 	"go.minekube.com/gate/pkg/gate/proto"
 )
@@ -26,8 +27,10 @@ type Writer interface {
 
 // NewWriter returns a new packet writer.
 func NewWriter(conn net.Conn, direction proto.Direction, writeTimeout time.Duration, compressionLevel int, log logr.Logger) Writer {
-	writeBuf := bufio.NewWriter(conn)
+	label := connLabel(direction == proto.ClientBound) // writes to clients are client bound
+	writeBuf := bufio.NewWriter(&countWriter{w: conn, c: bytesSent.WithLabelValues(label)})
 	return &writer{
+		packets:          packetsSent.WithLabelValues(label),
 		log:              log.WithName("writer"),
 		writeTimeout:     writeTimeout,
 		compressionLevel: compressionLevel,
@@ -43,9 +46,26 @@ type writer struct {
 	compressionLevel int
 	c                net.Conn // underlying connection
 	writeBuf         *bufio.Writer
+	packets          prometheus.Counter
 	*codec.Encoder
 }
 
+func (w *writer) WritePacket(packet proto.Packet) (n int, err error) {
+	n, err = w.Encoder.WritePacket(packet)
+	if err == nil {
+		w.packets.Inc()
+	}
+	return n, err
+}
+
+func (w *writer) Write(payload []byte) (n int, err error) {
+	n, err = w.Encoder.Write(payload)
+	if err == nil {
+		w.packets.Inc()
+	}
+	return n, err
+}
+
 func (w *writer) Flush() (err error) {
 	// Handle err in case the connection is
 	// already closed and can't write to.
@@ -35,6 +35,39 @@ func (p *ServerPing) MarshalJSON() ([]byte, error) {
 	})
 }
 
+func (p *ServerPing) UnmarshalJSON(data []byte) error {
+	type Alias ServerPing
+	out := &struct {
+		Description json.RawMessage `json:"description"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return err
+	}
+	p.Description = nil
+	if len(out.Description) == 0 || string(out.Description) == "null" {
+		return nil
+	}
+	// The description of a server may be any text component or a plain string.
+	c, err := util2.JsonCodec(p.Version.Protocol).Unmarshal(out.Description)
+	if err != nil {
+		var plain string
+		if json.Unmarshal(out.Description, &plain) != nil {
+			return err
+		}
+		p.Description = &component.Text{Content: plain}
+		return nil
+	}
+	if text, ok := c.(*component.Text); ok {
+		p.Description = text
+	} else {
+		p.Description = &component.Text{Extra: []component.Component{c}}
+	}
+	return nil
+}
+
 type Version struct {
 	Protocol proto.Protocol `json:"protocol"`
 	Name     string         `json:"name"`
@@ -0,0 +1,34 @@
+package ping
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/common/minecraft/component"
+)
+
+func TestServerPing_UnmarshalJSON(t *testing.T) {
+	p := new(ServerPing)
+	err := json.Unmarshal([]byte(`{
+		"version": {"name": "Paper 1.20.1", "protocol": 763},
+		"players": {"max": 20, "online": 1, "sample": [{"name": "Bob", "id": "4566e69f-c907-48ee-8d71-d7ba5aa00d20"}]},
+		"description": {"text": "A Minecraft Server"}
+	}`), p)
+	require.NoError(t, err)
+	require.Equal(t, "Paper 1.20.1", p.Version.Name)
+	require.EqualValues(t, 763, p.Version.Protocol)
+	require.Equal(t, 20, p.Players.Max)
+	require.Equal(t, 1, p.Players.Online)
+	require.Len(t, p.Players.Sample, 1)
+	require.Equal(t, "Bob", p.Players.Sample[0].Name)
+	require.Equal(t, "A Minecraft Server", p.Description.Content)
+}
+
+func TestServerPing_UnmarshalJSON_plainDescription(t *testing.T) {
+	p := new(ServerPing)
+	err := json.Unmarshal([]byte(`{"version":{"name":"1.8.8","protocol":47},"description":"Plain MOTD"}`), p)
+	require.NoError(t, err)
+	require.Nil(t, p.Players)
+	require.Equal(t, &component.Text{Content: "Plain MOTD"}, p.Description)
+}
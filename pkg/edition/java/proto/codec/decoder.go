@@ -1,6 +1,7 @@
 package codec
 
 import (
+	"bufio"
 	"bytes"
 	"compress/zlib"
 	"encoding/hex"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	"go.minekube.com/gate/pkg/edition/java/proto/util"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
@@ -25,8 +27,9 @@ type Decoder struct {
 	hexDump   bool // for debugging
 	direction proto.Direction
 
-	mu                   sync.Mutex // Protects following field and locked while reading a packet.
-	rd                   io.Reader  // The underlying reader.
+	mu                   sync.Mutex    // Protects following field and locked while reading a packet.
+	rd                   io.Reader     // The underlying reader.
+	br                   *bufio.Reader // The underlying reader if buffered, used to detect legacy pings.
 	registry             *state.ProtocolRegistry
 	state                *state.Registry
 	compression          bool
@@ -36,8 +39,12 @@ type Decoder struct {
 
 var _ proto.PacketDecoder = (*Decoder)(nil)
 
+// NewDecoder returns a new Decoder reading from r.
+// Legacy pings are only detected if r is a *bufio.Reader.
 func NewDecoder(r io.Reader, direction proto.Direction, log logr.Logger) *Decoder {
+	br, _ := r.(*bufio.Reader)
 	return &Decoder{
+		br:        br,
 		rd:        &fullReader{r}, // using the fullReader is essential here!
 		direction: direction,
 		state:     state.Handshake,
@@ -71,6 +78,7 @@ func (d *Decoder) setProtocol(protocol proto.Protocol) {
 func (d *Decoder) SetReader(rd io.Reader) {
 	d.mu.Lock()
 	d.rd = rd
+	d.br, _ = rd.(*bufio.Reader)
 	d.mu.Unlock()
 }
 
@@ -101,6 +109,12 @@ func (d *Decoder) readPacket() (ctx *proto.PacketContext, err error) {
 		}()
 	}
 
+	if d.state == state.Handshake && d.direction == proto.ServerBound {
+		if ctx, err = d.readLegacyPing(); ctx != nil || err != nil {
+			return ctx, err
+		}
+	}
+
 	payload, err := d.readPayload()
 	if err != nil {
 		return nil, err
@@ -112,6 +126,50 @@ func (d *Decoder) readPacket() (ctx *proto.PacketContext, err error) {
 	return d.decodePayload(payload)
 }
 
+// readLegacyPing reads a server list ping sent by clients older than 1.7
+// or returns a nil PacketContext if the next packet is no legacy ping.
+func (d *Decoder) readLegacyPing() (*proto.PacketContext, error) {
+	if d.br == nil {
+		return nil, nil
+	}
+	b, err := d.br.Peek(1)
+	if err != nil {
+		return nil, fmt.Errorf("error reading packet frame: %w", err)
+	}
+	if b[0] != packet.LegacyPingID {
+		return nil, nil
+	}
+	_, _ = d.br.Discard(1)
+
+	// The legacy formats can only be told apart by the number of bytes
+	// the client sent, since beta clients send the LegacyPingID only.
+	p := &packet.LegacyPing{Version: packet.LegacyPingVersion1_3}
+	ctx := &proto.PacketContext{
+		Direction:   d.direction,
+		Protocol:    version.Legacy.Protocol,
+		KnownPacket: true,
+		Packet:      p,
+	}
+	if d.br.Buffered() == 0 {
+		return ctx, nil
+	}
+	id, err := d.br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if id != packet.LegacyPingPayloadID {
+		return nil, errs.NewSilentErr("invalid legacy ping payload id 0x%02x", id)
+	}
+	p.Version = packet.LegacyPingVersion1_4
+	if d.br.Buffered() == 0 {
+		return ctx, nil
+	}
+	if err = p.Decode(ctx, d.br); err != nil {
+		return nil, errs.NewSilentErr("error decoding legacy ping: %w", err)
+	}
+	return ctx, nil
+}
+
 // can eventually receive an empty payload which packet should be skipped
 func (d *Decoder) readPayload() (payload []byte, err error) {
 	payload, err = readVarIntFrame(d.rd)
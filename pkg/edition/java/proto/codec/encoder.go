@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/zlib"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/go-logr/logr"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	"go.minekube.com/gate/pkg/edition/java/proto/util"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
@@ -23,6 +25,10 @@ const (
 	UncompressedCap                = VanillaMaximumUncompressedSize
 )
 
+// ErrPacketNotRegistered is returned by Encoder.WritePacket if the packet
+// is not registered in the current state and protocol of the encoder.
+var ErrPacketNotRegistered = errors.New("packet not registered")
+
 // Encoder is a synchronized packet encoder.
 type Encoder struct {
 	direction proto.Direction
@@ -65,10 +71,13 @@ func (e *Encoder) SetCompression(threshold, level int) (err error) {
 func (e *Encoder) WritePacket(packet proto.Packet) (n int, err error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if isLegacyDisconnect(packet) {
+		return e.writeLegacy(packet)
+	}
 	packetID, found := e.registry.PacketID(packet)
 	if !found {
-		return n, fmt.Errorf("packet id for type %T in protocol %s not registered in the %s state registry",
-			packet, e.registry.Protocol, e.state)
+		return n, fmt.Errorf("%w: packet id for type %T in protocol %s not registered in the %s state registry",
+			ErrPacketNotRegistered, packet, e.registry.Protocol, e.state)
 	}
 
 	pk := reflect.TypeOf(packet)
@@ -100,6 +109,26 @@ func (e *Encoder) WritePacket(packet proto.Packet) (n int, err error) {
 	return e.writeBuf(buf, pk) // packet id + data
 }
 
+func isLegacyDisconnect(p proto.Packet) bool {
+	_, ok := p.(*packet.LegacyDisconnect)
+	return ok
+}
+
+// writeLegacy writes a pre-1.7 packet without packet frame and compression.
+func (e *Encoder) writeLegacy(p proto.Packet) (n int, err error) {
+	buf := new(bytes.Buffer)
+	if err = p.Encode(&proto.PacketContext{
+		Direction:   e.direction,
+		Protocol:    version.Legacy.Protocol,
+		KnownPacket: true,
+		Packet:      p,
+	}, buf); err != nil {
+		return 0, err
+	}
+	m, err := buf.WriteTo(e.wr)
+	return int(m), err
+}
+
 // see https://wiki.vg/Protocol#Packet_format for details
 func (e *Encoder) writeBuf(payload *bytes.Buffer, pk reflect.Type) (n int, err error) {
 	if e.compression.enabled {
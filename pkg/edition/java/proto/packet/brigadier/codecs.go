@@ -107,6 +107,29 @@ var (
 			return &RegistryKeyArgumentType{Identifier: id}, nil
 		},
 	}
+	TimeArgumentPropertyCodec ArgumentPropertyCodec = &ArgumentPropertyCodecFuncs{
+		EncodeFn: func(wr io.Writer, v any, protocol proto.Protocol) error {
+			t, ok := v.(*TimeArgumentType)
+			if !ok {
+				return fmt.Errorf("expected *TimeArgumentType but got %T", v)
+			}
+			if protocol.GreaterEqual(version.Minecraft_1_19_4) {
+				return util.WriteInt32(wr, t.Min)
+			}
+			return nil
+		},
+		DecodeFn: func(rd io.Reader, protocol proto.Protocol) (brigodier.ArgumentType, error) {
+			t := new(TimeArgumentType)
+			if protocol.GreaterEqual(version.Minecraft_1_19_4) {
+				var err error
+				t.Min, err = util.ReadInt32(rd)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return t, nil
+		},
+	}
 
 	Float64ArgumentPropertyCodec ArgumentPropertyCodec = &ArgumentPropertyCodecFuncs{
 		EncodeFn: func(wr io.Writer, v any, protocol proto.Protocol) error {
@@ -35,7 +35,11 @@ func newArgIdentifier(id string, versions ...versionSet) (*ArgumentIdentifier, e
 			return nil, errors.New("invalid protocol version order")
 		}
 		for _, v := range version.Versions {
-			if v.Protocol >= current.version {
+			if v.Protocol < current.version {
+				continue
+			}
+			// Don't overwrite ids of newer version sets
+			if _, ok := identifier.idByProtocol[v.Protocol]; !ok {
 				identifier.idByProtocol[v.Protocol] = current.id
 			}
 		}
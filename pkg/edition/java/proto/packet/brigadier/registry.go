@@ -59,18 +59,26 @@ func (r *argPropReg) Encode(wr io.Writer, argType brigodier.ArgumentType, protoc
 			return err
 		}
 		return util.WriteBytes(wr, property.Data)
-	default:
-		codec, ok := r.byType[argType.String()]
-		id, ok2 := r.typeToID[argType.String()]
-		if !ok || !ok2 {
-			return fmt.Errorf("don't know how to encode %T", argType)
+	case *RegistryKeyArgumentType:
+		if property.argIdentifier == nil {
+			break
 		}
-		err := r.writeIdentifier(wr, id, protocol)
+		err := r.writeIdentifier(wr, property.argIdentifier, protocol)
 		if err != nil {
 			return err
 		}
-		return codec.Encode(wr, argType, protocol)
+		return RegistryKeyArgumentPropertyCodec.Encode(wr, property, protocol)
 	}
+	codec, ok := r.byType[argType.String()]
+	id, ok2 := r.typeToID[argType.String()]
+	if !ok || !ok2 {
+		return fmt.Errorf("don't know how to encode %T", argType)
+	}
+	err := r.writeIdentifier(wr, id, protocol)
+	if err != nil {
+		return err
+	}
+	return codec.Encode(wr, argType, protocol)
 }
 
 func Decode(rd io.Reader, protocol proto.Protocol) (brigodier.ArgumentType, error) {
@@ -94,6 +102,9 @@ func (r *argPropReg) Decode(rd io.Reader, protocol proto.Protocol) (brigodier.Ar
 			identifier: identifier,
 		}, nil
 	}
+	if t, ok := result.(*RegistryKeyArgumentType); ok {
+		t.argIdentifier = identifier
+	}
 	return result, nil
 }
 
@@ -207,15 +218,24 @@ func init() {
 	empty(id("minecraft:item_enchantment", mapSet(version.Minecraft_1_19, 39)))
 	empty(id("minecraft:entity_summon", mapSet(version.Minecraft_1_19, 40)))
 	empty(id("minecraft:dimension", mapSet(version.Minecraft_1_19, 41)))
-	empty(id("minecraft:time", mapSet(version.Minecraft_1_19, 42))) // added in 1.14
-
-	register(id("minecraft:resource_or_tag", mapSet(version.Minecraft_1_19, 43)), RegistryKeyArgument, RegistryKeyArgumentPropertyCodec)
-	register(id("minecraft:resource", mapSet(version.Minecraft_1_19, 44)), RegistryKeyArgument, RegistryKeyArgumentPropertyCodec)
-
-	empty(id("minecraft:template_mirror", mapSet(version.Minecraft_1_19, 45)))   // 1.19
-	empty(id("minecraft:template_rotation", mapSet(version.Minecraft_1_19, 46))) // 1.19
-
-	empty(id("minecraft:uuid", mapSet(version.Minecraft_1_19, 47))) // added in 1.16
+	empty(id("minecraft:gamemode", mapSet(version.Minecraft_1_19_4, 42))) // 1.19.4
+	register(id("minecraft:time", mapSet(version.Minecraft_1_19_4, 43), mapSet(version.Minecraft_1_19, 42)),
+		&TimeArgumentType{}, TimeArgumentPropertyCodec) // added in 1.14
+
+	register(id("minecraft:resource_or_tag", mapSet(version.Minecraft_1_19_4, 44), mapSet(version.Minecraft_1_19, 43)),
+		RegistryKeyArgument, RegistryKeyArgumentPropertyCodec)
+	emptyWithCodec(id("minecraft:resource_or_tag_key", mapSet(version.Minecraft_1_19_4, 45), mapSet(version.Minecraft_1_19_3, 44)),
+		RegistryKeyArgumentPropertyCodec) // 1.19.3
+	register(id("minecraft:resource", mapSet(version.Minecraft_1_19_4, 46), mapSet(version.Minecraft_1_19_3, 45), mapSet(version.Minecraft_1_19, 44)),
+		RegistryKeyArgument, RegistryKeyArgumentPropertyCodec)
+	emptyWithCodec(id("minecraft:resource_key", mapSet(version.Minecraft_1_19_4, 47), mapSet(version.Minecraft_1_19_3, 46)),
+		RegistryKeyArgumentPropertyCodec) // 1.19.3
+
+	empty(id("minecraft:template_mirror", mapSet(version.Minecraft_1_19_4, 48), mapSet(version.Minecraft_1_19_3, 47), mapSet(version.Minecraft_1_19, 45)))   // 1.19
+	empty(id("minecraft:template_rotation", mapSet(version.Minecraft_1_19_4, 49), mapSet(version.Minecraft_1_19_3, 48), mapSet(version.Minecraft_1_19, 46))) // 1.19
+	empty(id("minecraft:heightmap", mapSet(version.Minecraft_1_19_4, 50)))                                                                                   // 1.19.4
+
+	empty(id("minecraft:uuid", mapSet(version.Minecraft_1_19_4, 51), mapSet(version.Minecraft_1_19_3, 49), mapSet(version.Minecraft_1_19, 47))) // added in 1.16
 
 	// Crossstitch support
 	register(id("crossstitch:mod_argument", mapSet(version.Minecraft_1_19, -256)), &ModArgumentProperty{}, ModArgumentPropertyCodec)
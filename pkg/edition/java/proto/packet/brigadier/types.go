@@ -10,6 +10,10 @@ var (
 
 type RegistryKeyArgumentType struct {
 	Identifier string
+
+	// The argument identifier the type was decoded with as multiple
+	// arguments (e.g. minecraft:resource and minecraft:resource_or_tag) share this type.
+	argIdentifier *ArgumentIdentifier
 }
 
 func (r *RegistryKeyArgumentType) Parse(rd *brigodier.StringReader) (any, error) {
@@ -22,3 +26,11 @@ type ByteArgumentType byte
 
 func (b ByteArgumentType) Parse(*brigodier.StringReader) (interface{}, error) { return byte(0), nil }
 func (b ByteArgumentType) String() string                                     { return "byte" }
+
+// TimeArgumentType is the minecraft:time argument type.
+type TimeArgumentType struct {
+	Min int32 // 1.19.4+
+}
+
+func (t *TimeArgumentType) Parse(rd *brigodier.StringReader) (any, error) { return rd.ReadString() }
+func (t *TimeArgumentType) String() string                                { return "time" }
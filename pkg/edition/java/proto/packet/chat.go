@@ -356,6 +356,67 @@ func (p *SystemChat) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 
 var _ proto.Packet = (*SystemChat)(nil)
 
+// DisguisedChat is an unsigned chat message attributed to a sender
+// that the client displays like a player chat message (1.19.3+).
+type DisguisedChat struct {
+	Message    component.Component
+	ChatType   int                 // The id of the chat type in the chat type registry sent by the server.
+	Name       component.Component // The name of the sender.
+	TargetName component.Component // nil-able
+}
+
+// ChatTypeChat is the id of the vanilla "minecraft:chat" chat type
+// in the chat type registry sent by vanilla servers.
+const ChatTypeChat = 0
+
+func (p *DisguisedChat) Encode(c *proto.PacketContext, wr io.Writer) error {
+	err := util.WriteComponent(wr, c.Protocol, p.Message)
+	if err != nil {
+		return err
+	}
+	err = util.WriteVarInt(wr, p.ChatType)
+	if err != nil {
+		return err
+	}
+	err = util.WriteComponent(wr, c.Protocol, p.Name)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, p.TargetName != nil)
+	if err != nil {
+		return err
+	}
+	if p.TargetName != nil {
+		return util.WriteComponent(wr, c.Protocol, p.TargetName)
+	}
+	return nil
+}
+
+func (p *DisguisedChat) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
+	p.Message, err = util.ReadComponent(rd, c.Protocol)
+	if err != nil {
+		return err
+	}
+	p.ChatType, err = util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	p.Name, err = util.ReadComponent(rd, c.Protocol)
+	if err != nil {
+		return err
+	}
+	ok, err := util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	if ok {
+		p.TargetName, err = util.ReadComponent(rd, c.Protocol)
+	}
+	return err
+}
+
+var _ proto.Packet = (*DisguisedChat)(nil)
+
 type ServerChatPreview struct {
 	ID      int
 	Preview component.Component
@@ -483,7 +544,9 @@ type ChatBuilder struct {
 	signedCommand     *crypto.SignedChatCommand
 	typ               MessageType
 	sender            *uuid.UUID
+	senderName        component.Component // 1.19.3+
 	timestamp         time.Time
+	lastSeenMessages  LastSeenMessages // 1.19.3+
 }
 
 func NewChatBuilder(version proto.Protocol) *ChatBuilder {
@@ -519,15 +582,35 @@ func (b *ChatBuilder) Time(timestamp time.Time) *ChatBuilder {
 	b.timestamp = timestamp
 	return b
 }
+func (b *ChatBuilder) LastSeenMessages(l LastSeenMessages) *ChatBuilder {
+	b.lastSeenMessages = l
+	return b
+}
 func (b *ChatBuilder) AsPlayer(sender uuid.UUID) *ChatBuilder {
 	b.sender = &sender
 	return b
 }
 func (b *ChatBuilder) AsServer() *ChatBuilder {
 	b.sender = nil
+	b.senderName = nil
 	return b
 }
 
+// SenderName sets the name of the sender to display chat messages to 1.19.3+ clients
+// as unsigned player chat messages instead of system messages.
+func (b *ChatBuilder) SenderName(name component.Component) *ChatBuilder {
+	b.senderName = name
+	return b
+}
+
+// Sender returns the sender set by AsPlayer.
+func (b *ChatBuilder) Sender() (uuid.UUID, bool) {
+	if b.sender == nil {
+		return uuid.Nil, false
+	}
+	return *b.sender, true
+}
+
 // ToClient creates a packet which can be sent to the client;
 // using the provided information in the builder.
 func (b *ChatBuilder) ToClient() proto.Packet {
@@ -536,8 +619,19 @@ func (b *ChatBuilder) ToClient() proto.Packet {
 		msg = &component.Text{Content: b.message}
 	}
 
+	if b.protocol.GreaterEqual(version.Minecraft_1_19_3) &&
+		b.typ == ChatMessageType && b.sender != nil && b.senderName != nil {
+		// Player chat messages must be signed, but disguised
+		// chat messages are displayed like them without signature
+		return &DisguisedChat{
+			Message:  msg,
+			ChatType: ChatTypeChat,
+			Name:     b.senderName,
+		}
+	}
 	if b.protocol.GreaterEqual(version.Minecraft_1_19) {
-		// hard override chat > system for now
+		// Unsigned chat messages from players are rejected or flagged
+		// as not secure by 1.19+ clients, so send them as system messages
 		t := b.typ
 		if t == ChatMessageType {
 			t = SystemMessageType
@@ -564,6 +658,21 @@ func (b *ChatBuilder) ToClient() proto.Packet {
 // ToServer creates a packet which can be sent to the server;
 // using the provided information in the builder.
 func (b *ChatBuilder) ToServer() proto.Packet {
+	if b.protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		// Messages modified by the proxy can't be signed by the player's chat session
+		if strings.HasPrefix(b.message, "/") {
+			return &SessionPlayerCommand{
+				Command:          strings.TrimPrefix(b.message, "/"),
+				Timestamp:        b.timestamp,
+				LastSeenMessages: b.lastSeenMessages,
+			}
+		}
+		return &SessionPlayerChat{
+			Message:          b.message,
+			Timestamp:        b.timestamp,
+			LastSeenMessages: b.lastSeenMessages,
+		}
+	}
 	if b.protocol.GreaterEqual(version.Minecraft_1_19) {
 		if b.signedChatMessage != nil {
 			return toPlayerChat(b.signedChatMessage)
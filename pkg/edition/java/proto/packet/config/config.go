@@ -0,0 +1,31 @@
+// Package config contains the packets of the configuration state added in 1.20.2.
+package config
+
+import (
+	"io"
+
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// StartUpdate is sent by the server in the play state to
+// switch the client back to the configuration state.
+// The client acknowledges it with a FinishedUpdate.
+type StartUpdate struct{}
+
+func (s *StartUpdate) Encode(*proto.PacketContext, io.Writer) error { return nil }
+func (s *StartUpdate) Decode(*proto.PacketContext, io.Reader) error { return nil }
+
+// FinishedUpdate is sent by the server to end the configuration state
+// and acknowledged by the client with a FinishedUpdate before both switch
+// to the play state.
+//
+// It is also sent by the client in the play state to acknowledge a StartUpdate.
+type FinishedUpdate struct{}
+
+func (f *FinishedUpdate) Encode(*proto.PacketContext, io.Writer) error { return nil }
+func (f *FinishedUpdate) Decode(*proto.PacketContext, io.Reader) error { return nil }
+
+var (
+	_ proto.Packet = (*StartUpdate)(nil)
+	_ proto.Packet = (*FinishedUpdate)(nil)
+)
@@ -31,6 +31,13 @@ type JoinGame struct {
 	SimulationDistance   int                // 1.18+
 	LastDeadPosition     *DeathPosition     // 1.19+
 	ChatTypeRegistry     util.NBT           // placeholder, 1.19+ // compound binary tag
+	// Registry is the complete registry container sent by the server (1.16-1.20.1).
+	// If set, it is encoded as is instead of the container composed of
+	// DimensionRegistry, BiomeRegistry and ChatTypeRegistry to keep the
+	// registries the proxy doesn't know about (e.g. damage types in 1.19.4+).
+	Registry          util.NBT
+	PortalCooldown    int  // 1.20+
+	DoLimitedCrafting bool // 1.20.2+
 }
 
 type DeathPosition struct {
@@ -90,6 +97,10 @@ const (
 )
 
 func (j *JoinGame) Encode(c *proto.PacketContext, wr io.Writer) error {
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		// Registries are sent in the configuration state since 1.20.2
+		return j.encode1202Up(wr)
+	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_16) {
 		// Minecraft 1.16 and above have significantly more complicated logic for writing this packet,
 		// so separate it out.
@@ -130,26 +141,10 @@ func (j *JoinGame) encode116Up(c *proto.PacketContext, wr io.Writer) error {
 	if err != nil {
 		return err
 	}
-	encodedDimensionRegistry, err := j.DimensionRegistry.encode(c.Protocol)
+	registryContainer, err := j.registryContainer(c.Protocol)
 	if err != nil {
 		return err
 	}
-	registryContainer := util.NBT{}
-	if c.Protocol.GreaterEqual(version.Minecraft_1_16_2) {
-		registryContainer[dimTypeKey] = util.NBT{
-			"type":  dimTypeKey,
-			"value": encodedDimensionRegistry,
-		}
-		if j.BiomeRegistry == nil {
-			return errors.New("missing biome registry")
-		}
-		registryContainer[biomeKey] = j.BiomeRegistry
-		if c.Protocol.GreaterEqual(version.Minecraft_1_19) {
-			registryContainer[chatTypeKey] = j.ChatTypeRegistry
-		}
-	} else {
-		registryContainer["dimension"] = encodedDimensionRegistry
-	}
 	nbtEncoder := nbt.NewEncoderWithEncoding(wr, nbt.BigEndian)
 	err = nbtEncoder.Encode(registryContainer)
 	if err != nil {
@@ -226,9 +221,122 @@ func (j *JoinGame) encode116Up(c *proto.PacketContext, wr io.Writer) error {
 			return err
 		}
 	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20) {
+		err = util.WriteVarInt(wr, j.PortalCooldown)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// registryContainer returns the registry container to encode for 1.16-1.20.1.
+func (j *JoinGame) registryContainer(protocol proto.Protocol) (util.NBT, error) {
+	if j.Registry != nil {
+		return j.Registry, nil
+	}
+	encodedDimensionRegistry, err := j.DimensionRegistry.encode(protocol)
+	if err != nil {
+		return nil, err
+	}
+	registryContainer := util.NBT{}
+	if protocol.GreaterEqual(version.Minecraft_1_16_2) {
+		registryContainer[dimTypeKey] = util.NBT{
+			"type":  dimTypeKey,
+			"value": encodedDimensionRegistry,
+		}
+		if j.BiomeRegistry == nil {
+			return nil, errors.New("missing biome registry")
+		}
+		registryContainer[biomeKey] = j.BiomeRegistry
+		if protocol.GreaterEqual(version.Minecraft_1_19) {
+			registryContainer[chatTypeKey] = j.ChatTypeRegistry
+		}
+	} else {
+		registryContainer["dimension"] = encodedDimensionRegistry
+	}
+	return registryContainer, nil
+}
+
+func (j *JoinGame) encode1202Up(wr io.Writer) error {
+	err := util.WriteInt(wr, j.EntityID)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.Hardcore)
+	if err != nil {
+		return err
+	}
+	var levelNames []string
+	if j.DimensionRegistry != nil {
+		levelNames = j.DimensionRegistry.LevelNames
+	}
+	err = util.WriteStrings(wr, levelNames)
+	if err != nil {
+		return err
+	}
+	err = util.WriteVarInt(wr, j.MaxPlayers)
+	if err != nil {
+		return err
+	}
+	err = util.WriteVarInt(wr, j.ViewDistance)
+	if err != nil {
+		return err
+	}
+	err = util.WriteVarInt(wr, j.SimulationDistance)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.ReducedDebugInfo)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.ShowRespawnScreen)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.DoLimitedCrafting)
+	if err != nil {
+		return err
+	}
+	err = util.WriteString(wr, j.DimensionInfo.RegistryIdentifier)
+	if err != nil {
+		return err
+	}
+	if j.DimensionInfo.LevelName == nil {
+		return errors.New("dimension info level name must not be nil")
+	}
+	err = util.WriteString(wr, *j.DimensionInfo.LevelName)
+	if err != nil {
+		return err
+	}
+	err = util.WriteInt64(wr, j.PartialHashedSeed)
+	if err != nil {
+		return err
+	}
+	err = util.WriteByte(wr, byte(j.Gamemode))
+	if err != nil {
+		return err
+	}
+	err = util.WriteByte(wr, byte(j.PreviousGamemode))
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.DimensionInfo.DebugType)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, j.DimensionInfo.Flat)
+	if err != nil {
+		return err
+	}
+	err = j.LastDeadPosition.encode(wr)
+	if err != nil {
+		return err
+	}
+	return util.WriteVarInt(wr, j.PortalCooldown)
+}
+
 func (j *JoinGame) encodeLegacy(c *proto.PacketContext, wr io.Writer) error {
 	err := util.WriteInt32(wr, int32(j.EntityID))
 	if err != nil {
@@ -299,6 +407,9 @@ func (j *JoinGame) encodeLegacy(c *proto.PacketContext, wr io.Writer) error {
 }
 
 func (j *JoinGame) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		return j.decode1202Up(rd)
+	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_16) {
 		// Minecraft 1.16 and above have significantly more complicated logic for reading this packet,
 		// so separate it out.
@@ -452,6 +563,7 @@ func (j *JoinGame) decode116Up(c *proto.PacketContext, rd io.Reader) (err error)
 		Dimensions: readData,
 		LevelNames: levelNames,
 	}
+	j.Registry = registryContainer
 	var dimensionIdentifier, levelName string
 	if c.Protocol.GreaterEqual(version.Minecraft_1_16_2) &&
 		c.Protocol.Lower(version.Minecraft_1_19) {
@@ -536,7 +648,93 @@ func (j *JoinGame) decode116Up(c *proto.PacketContext, rd io.Reader) (err error)
 			return err
 		}
 	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20) {
+		j.PortalCooldown, err = util.ReadVarInt(rd)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (j *JoinGame) decode1202Up(rd io.Reader) (err error) {
+	j.EntityID, err = util.ReadInt(rd)
+	if err != nil {
+		return err
+	}
+	j.Hardcore, err = util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	levelNames, err := util.ReadStringArray(rd)
+	if err != nil {
+		return err
+	}
+	j.DimensionRegistry = &DimensionRegistry{LevelNames: levelNames}
+	j.MaxPlayers, err = util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	j.ViewDistance, err = util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	j.SimulationDistance, err = util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	j.ReducedDebugInfo, err = util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	j.ShowRespawnScreen, err = util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	j.DoLimitedCrafting, err = util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	dimensionIdentifier, err := util.ReadString(rd)
+	if err != nil {
+		return err
+	}
+	levelName, err := util.ReadString(rd)
+	if err != nil {
+		return err
+	}
+	j.PartialHashedSeed, err = util.ReadInt64(rd)
+	if err != nil {
+		return err
+	}
+	if err = j.readGamemode(rd); err != nil {
+		return err
+	}
+	previousGamemode, err := util.ReadByte(rd)
+	if err != nil {
+		return err
+	}
+	j.PreviousGamemode = int16(previousGamemode)
+	debug, err := util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	flat, err := util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	j.DimensionInfo = &DimensionInfo{
+		RegistryIdentifier: dimensionIdentifier,
+		LevelName:          &levelName,
+		Flat:               flat,
+		DebugType:          debug,
+	}
+	j.LastDeadPosition, err = decodeDeathPosition(rd)
+	if err != nil {
+		return err
+	}
+	j.PortalCooldown, err = util.ReadVarInt(rd)
+	return err
+}
+
 var _ proto.Packet = (*JoinGame)(nil)
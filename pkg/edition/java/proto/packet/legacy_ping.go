@@ -0,0 +1,228 @@
+package packet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"go.minekube.com/common/minecraft/component/codec/legacy"
+
+	"go.minekube.com/gate/pkg/edition/java/ping"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/util/netutil"
+)
+
+// Legacy ping packet ids sent by clients older than 1.7.
+// See https://wiki.vg/Server_List_Ping#1.6
+const (
+	LegacyPingID              byte = 0xFE // Starts every legacy ping.
+	LegacyPingPayloadID       byte = 0x01 // Sent by 1.4+ clients after the LegacyPingID.
+	LegacyPluginMessageID     byte = 0xFA // Sent by 1.6 clients after the LegacyPingPayloadID.
+	LegacyDisconnectID        byte = 0xFF // The kick packet used to respond to legacy pings.
+	legacyPingHostChannel          = "MC|PingHost"
+	legacyDisconnectDelimiter      = "\x00"
+)
+
+// LegacyPingVersion is the format of a legacy ping.
+type LegacyPingVersion byte
+
+const (
+	// LegacyPingVersion1_3 is sent by beta 1.8 to 1.3 clients (0xFE).
+	LegacyPingVersion1_3 LegacyPingVersion = iota
+	// LegacyPingVersion1_4 is sent by 1.4 and 1.5 clients (0xFE 0x01).
+	LegacyPingVersion1_4
+	// LegacyPingVersion1_6 is sent by 1.6 clients (0xFE 0x01 0xFA ...).
+	LegacyPingVersion1_6
+)
+
+// LegacyPing is a server list ping sent by clients older than 1.7.
+// It is not framed like other packets, the codec.Decoder detects the
+// prefix and only the 1.6 plugin message following it is decoded by LegacyPing.
+type LegacyPing struct {
+	Version     LegacyPingVersion
+	VirtualHost net.Addr // The address the client connected to, only sent by 1.6 clients.
+}
+
+func (l *LegacyPing) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	if l.Version != LegacyPingVersion1_6 {
+		return nil // has no data
+	}
+	host, port := "", uint16(0)
+	if l.VirtualHost != nil {
+		host, port = netutil.HostPort(l.VirtualHost)
+	}
+	if err := util.WriteByte(wr, LegacyPluginMessageID); err != nil {
+		return err
+	}
+	if err := writeLegacyString(wr, legacyPingHostChannel); err != nil {
+		return err
+	}
+	// Length of the following data: protocol byte + host string + port int
+	if err := util.WriteInt16(wr, int16(1+2+2*len(utf16.Encode([]rune(host)))+4)); err != nil {
+		return err
+	}
+	if err := util.WriteByte(wr, 0); err != nil { // unused protocol version
+		return err
+	}
+	if err := writeLegacyString(wr, host); err != nil {
+		return err
+	}
+	return util.WriteInt32(wr, int32(port))
+}
+
+func (l *LegacyPing) Decode(_ *proto.PacketContext, rd io.Reader) (err error) {
+	id, err := util.ReadByte(rd)
+	if err != nil {
+		return err
+	}
+	if id != LegacyPluginMessageID {
+		return fmt.Errorf("expected legacy plugin message id 0x%02x, got 0x%02x", LegacyPluginMessageID, id)
+	}
+	channel, err := readLegacyString(rd)
+	if err != nil {
+		return err
+	}
+	if channel != legacyPingHostChannel {
+		return fmt.Errorf("unexpected legacy ping channel %q", channel)
+	}
+	// Skip data length and protocol version
+	if _, err = util.ReadInt16(rd); err != nil {
+		return err
+	}
+	if _, err = util.ReadByte(rd); err != nil {
+		return err
+	}
+	host, err := readLegacyString(rd)
+	if err != nil {
+		return err
+	}
+	port, err := util.ReadInt32(rd)
+	if err != nil {
+		return err
+	}
+	l.Version = LegacyPingVersion1_6
+	l.VirtualHost = netutil.NewAddr(net.JoinHostPort(host, strconv.Itoa(int(port))), "tcp")
+	return nil
+}
+
+// LegacyDisconnect is the kick packet sent in response to a LegacyPing.
+// Like the LegacyPing it is not framed and written as is by the codec.Encoder.
+type LegacyDisconnect struct {
+	Reason string
+}
+
+func (l *LegacyDisconnect) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	if err := util.WriteByte(wr, LegacyDisconnectID); err != nil {
+		return err
+	}
+	return writeLegacyString(wr, l.Reason)
+}
+
+func (l *LegacyDisconnect) Decode(_ *proto.PacketContext, rd io.Reader) (err error) {
+	id, err := util.ReadByte(rd)
+	if err != nil {
+		return err
+	}
+	if id != LegacyDisconnectID {
+		return fmt.Errorf("expected legacy disconnect id 0x%02x, got 0x%02x", LegacyDisconnectID, id)
+	}
+	l.Reason, err = readLegacyString(rd)
+	return
+}
+
+var (
+	_ proto.Packet = (*LegacyPing)(nil)
+	_ proto.Packet = (*LegacyDisconnect)(nil)
+)
+
+// LegacyDisconnectFromPing creates the LegacyDisconnect response
+// containing the server list ping in the format of the LegacyPingVersion.
+func LegacyDisconnectFromPing(p *ping.ServerPing, v LegacyPingVersion) *LegacyDisconnect {
+	var online, max int
+	if p.Players != nil {
+		online, max = p.Players.Online, p.Players.Max
+	}
+	motd := new(strings.Builder)
+	if p.Description != nil {
+		_ = (&legacy.Legacy{}).Marshal(motd, p.Description)
+	}
+	// Legacy clients only show the first line
+	description, _, _ := strings.Cut(motd.String(), "\n")
+
+	if v == LegacyPingVersion1_3 {
+		// The section symbol is used as delimiter and must be removed from the description
+		return &LegacyDisconnect{Reason: strings.Join([]string{
+			stripLegacyFormatting(description),
+			strconv.Itoa(online),
+			strconv.Itoa(max),
+		}, "§")}
+	}
+	return &LegacyDisconnect{Reason: strings.Join([]string{
+		"§1",
+		strconv.Itoa(int(p.Version.Protocol)),
+		p.Version.Name,
+		description,
+		strconv.Itoa(online),
+		strconv.Itoa(max),
+	}, legacyDisconnectDelimiter)}
+}
+
+// stripLegacyFormatting removes all '§' formatting codes from s.
+func stripLegacyFormatting(s string) string {
+	b := new(strings.Builder)
+	skip := false
+	for _, r := range s {
+		switch {
+		case skip:
+			skip = false
+		case r == '§':
+			skip = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ErrLegacyStringTooLong is returned when a legacy string exceeds the maximum length.
+var ErrLegacyStringTooLong = errors.New("legacy string too long")
+
+// writeLegacyString writes s as UTF-16BE string prefixed with its length in code units.
+func writeLegacyString(wr io.Writer, s string) error {
+	chars := utf16.Encode([]rune(s))
+	if len(chars) > 0x7FFF {
+		return ErrLegacyStringTooLong
+	}
+	if err := util.WriteInt16(wr, int16(len(chars))); err != nil {
+		return err
+	}
+	for _, c := range chars {
+		if err := util.WriteUint16(wr, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLegacyString reads a string written by writeLegacyString.
+func readLegacyString(rd io.Reader) (string, error) {
+	length, err := util.ReadInt16(rd)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 {
+		return "", fmt.Errorf("invalid legacy string length %d", length)
+	}
+	chars := make([]uint16, length)
+	for i := range chars {
+		if chars[i], err = util.ReadUint16(rd); err != nil {
+			return "", err
+		}
+	}
+	return string(utf16.Decode(chars)), nil
+}
@@ -16,8 +16,8 @@ import (
 
 type ServerLogin struct {
 	Username  string
-	PlayerKey crypto.IdentifiedKey // 1.19+
-	HolderID  uuid.UUID            // Used for key revision 2
+	PlayerKey crypto.IdentifiedKey // 1.19-1.19.2
+	HolderID  uuid.UUID            // Used for key revision 2, the player's uuid in 1.19.3+
 }
 
 var errEmptyUsername = errs.NewSilentErr("empty username")
@@ -32,6 +32,20 @@ func (s *ServerLogin) Encode(c *proto.PacketContext, wr io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		return util.WriteUUID(wr, s.HolderID)
+	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		// Player keys were removed in favor of chat sessions
+		err = util.WriteBool(wr, s.HolderID != uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if s.HolderID != uuid.Nil {
+			return util.WriteUUID(wr, s.HolderID)
+		}
+		return nil
+	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_19) {
 		err = util.WriteBool(wr, s.PlayerKey != nil)
 		if err != nil {
@@ -67,6 +81,20 @@ func (s *ServerLogin) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 		return errEmptyUsername
 	}
 
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		s.HolderID, err = util.ReadUUID(rd)
+		return err
+	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		ok, err := util.ReadBool(rd)
+		if err != nil {
+			return err
+		}
+		if ok {
+			s.HolderID, err = util.ReadUUID(rd)
+		}
+		return err
+	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_19) {
 		ok, err := util.ReadBool(rd)
 		if err != nil {
@@ -98,7 +126,7 @@ func (s *ServerLogin) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 type EncryptionResponse struct {
 	SharedSecret []byte
 	VerifyToken  []byte
-	Salt         *int64 // 1.19+
+	Salt         *int64 // 1.19-1.19.2
 }
 
 func (e *EncryptionResponse) Encode(c *proto.PacketContext, wr io.Writer) error {
@@ -107,7 +135,7 @@ func (e *EncryptionResponse) Encode(c *proto.PacketContext, wr io.Writer) error
 		if err != nil {
 			return err
 		}
-		if c.Protocol.GreaterEqual(version.Minecraft_1_19) {
+		if c.Protocol.GreaterEqual(version.Minecraft_1_19) && c.Protocol.Lower(version.Minecraft_1_19_3) {
 			err = util.WriteBool(wr, e.Salt == nil) // yes, write true if no salt
 			if err != nil {
 				return err
@@ -135,7 +163,7 @@ func (e *EncryptionResponse) Decode(c *proto.PacketContext, rd io.Reader) (err e
 		if err != nil {
 			return
 		}
-		if c.Protocol.GreaterEqual(version.Minecraft_1_19) {
+		if c.Protocol.GreaterEqual(version.Minecraft_1_19) && c.Protocol.Lower(version.Minecraft_1_19_3) {
 			var ok bool
 			ok, err = util.ReadBool(rd)
 			if err != nil {
@@ -353,7 +381,15 @@ func (l *LoginPluginMessage) Decode(_ *proto.PacketContext, rd io.Reader) (err e
 	return
 }
 
+// LoginAcknowledged is sent by 1.20.2+ clients in response to the
+// ServerLoginSuccess to switch to the configuration state.
+type LoginAcknowledged struct{}
+
+func (l *LoginAcknowledged) Encode(*proto.PacketContext, io.Writer) error { return nil }
+func (l *LoginAcknowledged) Decode(*proto.PacketContext, io.Reader) error { return nil }
+
 var _ proto.Packet = (*ServerLogin)(nil)
+var _ proto.Packet = (*LoginAcknowledged)(nil)
 var _ proto.Packet = (*ServerLoginSuccess)(nil)
 var _ proto.Packet = (*LoginPluginMessage)(nil)
 var _ proto.Packet = (*LoginPluginResponse)(nil)
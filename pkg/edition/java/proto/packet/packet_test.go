@@ -21,14 +21,17 @@ import (
 	"go.minekube.com/brigodier"
 	"go.minekube.com/common/minecraft/color"
 	"go.minekube.com/common/minecraft/component"
+	"go.minekube.com/gate/pkg/edition/java/ping"
 	"go.minekube.com/gate/pkg/edition/java/profile"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/bossbar"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/config"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/title"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
 	"go.minekube.com/gate/pkg/edition/java/proxy/crypto"
 	"go.minekube.com/gate/pkg/edition/java/proxy/crypto/keyrevision"
 	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/util/netutil"
 	"go.minekube.com/gate/pkg/util/uuid"
 )
 
@@ -175,7 +178,7 @@ var packets = []proto.Packet{
 		ReducedDebugInfo:     true,
 		ShowRespawnScreen:    true,
 		DimensionRegistry:    mustFake(&DimensionRegistry{}).(*DimensionRegistry),
-		DimensionInfo:        mustFake(&DimensionInfo{}).(*DimensionInfo),
+		DimensionInfo:        &DimensionInfo{RegistryIdentifier: "minecraft:overworld", LevelName: strPtr("world")},
 		CurrentDimensionData: mustFake(&DimensionData{}).(*DimensionData),
 		PreviousGamemode:     2,
 		BiomeRegistry: map[string]any{
@@ -199,13 +202,72 @@ var packets = []proto.Packet{
 		Component: &component.Text{Content: "Preview", S: component.Style{Color: color.Red}},
 		Type:      SystemMessageType,
 	},
+	&DisguisedChat{
+		Message:  &component.Text{Content: "Hello", S: component.Style{Color: color.Red}},
+		ChatType: ChatTypeChat,
+		Name:     &component.Text{Content: "Notch"},
+	},
 	&PlayerChatCompletion{},
 	&ServerData{
 		Description:        &component.Text{Content: "Description", S: component.Style{Color: color.Red}},
-		Favicon:            "Favicon",
+		Favicon:            "data:image/png;base64,AAAA",
 		PreviewsChat:       true,
 		SecureChatEnforced: true,
 	},
+	&SessionPlayerChat{
+		Message:   "Hello",
+		Timestamp: time.UnixMilli(1700000000000),
+		Salt:      42,
+		Signed:    true,
+		Signature: bytes.Repeat([]byte{1}, 256),
+		LastSeenMessages: LastSeenMessages{
+			Offset:       3,
+			Acknowledged: [3]byte{1, 2, 3},
+		},
+	},
+	&SessionPlayerCommand{
+		Command:   "msg Alice hi",
+		Timestamp: time.UnixMilli(1700000000000),
+		Salt:      42,
+		ArgumentSignatures: []ArgumentSignature{
+			{Name: "message", Signature: bytes.Repeat([]byte{2}, 256)},
+		},
+		LastSeenMessages: LastSeenMessages{Offset: 1},
+	},
+	&ChatAcknowledgement{Offset: 5},
+	&UpsertPlayerInfo{
+		ActionSet: []UpsertPlayerInfoAction{
+			AddPlayerUpsertPlayerInfoAction,
+			UpdateGameModeUpsertPlayerInfoAction,
+			UpdateListedUpsertPlayerInfoAction,
+			UpdateLatencyUpsertPlayerInfoAction,
+			UpdateDisplayNameUpsertPlayerInfoAction,
+		},
+		Entries: []*UpsertPlayerInfoEntry{
+			{
+				ID: uuid.New(),
+				Profile: &profile.GameProfile{
+					Name: "Alice",
+					Properties: []profile.Property{
+						{Name: "textures", Value: "value", Signature: "signature"},
+					},
+				},
+				Listed:      true,
+				Latency:     42,
+				GameMode:    1,
+				DisplayName: &component.Text{Content: "Alice", S: component.Style{Color: color.Green}},
+			},
+		},
+	},
+	&RemovePlayerInfo{PlayersToRemove: []uuid.UUID{uuid.New(), uuid.New()}},
+	&LoginAcknowledged{},
+	&config.StartUpdate{},
+	&config.FinishedUpdate{},
+	&LegacyPing{
+		Version:     LegacyPingVersion1_6,
+		VirtualHost: netutil.NewAddr("localhost:25565", "tcp"),
+	},
+	&LegacyDisconnect{Reason: "§1\x00760\x00Gate\x00§bA Gate Proxy\x000\x001000"},
 	&bossbar.BossBar{
 		ID:      uuid.New(),
 		Action:  bossbar.UpdateStyleAction,
@@ -346,3 +408,14 @@ func mustFake(a any) any {
 	return a
 }
 func mustFakeStr() string { return *mustFake(strPtr("")).(*string) }
+
+func TestLegacyDisconnectFromPing(t *testing.T) {
+	p := &ping.ServerPing{
+		Version: ping.Version{Protocol: 760, Name: "Gate"},
+		Players: &ping.Players{Online: 3, Max: 10},
+	}
+	require.Equal(t, "§1\x00760\x00Gate\x00\x003\x0010",
+		LegacyDisconnectFromPing(p, LegacyPingVersion1_6).Reason)
+	require.Equal(t, "§3§10", LegacyDisconnectFromPing(p, LegacyPingVersion1_3).Reason)
+	require.Equal(t, "A Gate Proxy", stripLegacyFormatting("§bA §lGate§r Proxy"))
+}
@@ -0,0 +1,257 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+
+	"go.minekube.com/common/minecraft/component"
+	"go.minekube.com/gate/pkg/edition/java/profile"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proxy/crypto"
+	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/util/uuid"
+)
+
+// UpsertPlayerInfo replaced the PlayerListItem packet for adding and updating
+// tab list entries in 1.19.3+.
+type UpsertPlayerInfo struct {
+	ActionSet []UpsertPlayerInfoAction // ordered by action, must not contain duplicates
+	Entries   []*UpsertPlayerInfoEntry
+}
+
+// UpsertPlayerInfoAction is an action of an UpsertPlayerInfo packet.
+type UpsertPlayerInfoAction int
+
+const (
+	AddPlayerUpsertPlayerInfoAction UpsertPlayerInfoAction = iota
+	InitializeChatUpsertPlayerInfoAction
+	UpdateGameModeUpsertPlayerInfoAction
+	UpdateListedUpsertPlayerInfoAction
+	UpdateLatencyUpsertPlayerInfoAction
+	UpdateDisplayNameUpsertPlayerInfoAction
+
+	upsertPlayerInfoActionCount = iota
+)
+
+// UpsertPlayerInfoEntry is an entry of an UpsertPlayerInfo packet.
+// Only the fields of the packet's actions are set.
+type UpsertPlayerInfoEntry struct {
+	ID          uuid.UUID
+	Profile     *profile.GameProfile // AddPlayer
+	Listed      bool                 // UpdateListed
+	Latency     int                  // UpdateLatency
+	GameMode    int                  // UpdateGameMode
+	DisplayName component.Component  // UpdateDisplayName, nil-able
+	ChatSession *RemoteChatSession   // InitializeChat, nil-able
+}
+
+// RemoteChatSession is the chat session of a player shown to other players.
+type RemoteChatSession struct {
+	ID  uuid.UUID
+	Key crypto.IdentifiedKey
+}
+
+// ContainsAction returns true if the action is in the packet's action set.
+func (u *UpsertPlayerInfo) ContainsAction(action UpsertPlayerInfoAction) bool {
+	for _, a := range u.ActionSet {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *UpsertPlayerInfo) Encode(c *proto.PacketContext, wr io.Writer) error {
+	var bitSet byte
+	for _, action := range u.ActionSet {
+		if action < 0 || action >= upsertPlayerInfoActionCount {
+			return fmt.Errorf("unknown UpsertPlayerInfoAction %d", action)
+		}
+		bitSet |= 1 << action
+	}
+	err := util.WriteByte(wr, bitSet)
+	if err != nil {
+		return err
+	}
+	err = util.WriteVarInt(wr, len(u.Entries))
+	if err != nil {
+		return err
+	}
+	for _, entry := range u.Entries {
+		err = util.WriteUUID(wr, entry.ID)
+		if err != nil {
+			return err
+		}
+		// Actions must be written in the order of the bitset
+		for action := UpsertPlayerInfoAction(0); action < upsertPlayerInfoActionCount; action++ {
+			if bitSet&(1<<action) == 0 {
+				continue
+			}
+			err = entry.encodeAction(c, wr, action)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *UpsertPlayerInfoEntry) encodeAction(c *proto.PacketContext, wr io.Writer, action UpsertPlayerInfoAction) (err error) {
+	switch action {
+	case AddPlayerUpsertPlayerInfoAction:
+		if e.Profile == nil {
+			return fmt.Errorf("missing profile of entry %s", e.ID)
+		}
+		err = util.WriteString(wr, e.Profile.Name)
+		if err != nil {
+			return err
+		}
+		return util.WriteProperties(wr, e.Profile.Properties)
+	case InitializeChatUpsertPlayerInfoAction:
+		err = util.WriteBool(wr, e.ChatSession != nil)
+		if err != nil {
+			return err
+		}
+		if e.ChatSession != nil {
+			err = util.WriteUUID(wr, e.ChatSession.ID)
+			if err != nil {
+				return err
+			}
+			return crypto.WritePlayerKey(wr, e.ChatSession.Key)
+		}
+		return nil
+	case UpdateGameModeUpsertPlayerInfoAction:
+		return util.WriteVarInt(wr, e.GameMode)
+	case UpdateListedUpsertPlayerInfoAction:
+		return util.WriteBool(wr, e.Listed)
+	case UpdateLatencyUpsertPlayerInfoAction:
+		return util.WriteVarInt(wr, e.Latency)
+	case UpdateDisplayNameUpsertPlayerInfoAction:
+		return writeDisplayName(wr, e.DisplayName, c.Protocol)
+	default:
+		return fmt.Errorf("unknown UpsertPlayerInfoAction %d", action)
+	}
+}
+
+func (u *UpsertPlayerInfo) Decode(c *proto.PacketContext, rd io.Reader) error {
+	bitSet, err := util.ReadByte(rd)
+	if err != nil {
+		return err
+	}
+	u.ActionSet = nil
+	for action := UpsertPlayerInfoAction(0); action < upsertPlayerInfoActionCount; action++ {
+		if bitSet&(1<<action) != 0 {
+			u.ActionSet = append(u.ActionSet, action)
+		}
+	}
+	length, err := util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return fmt.Errorf("invalid number of entries %d", length)
+	}
+	u.Entries = nil
+	for i := 0; i < length; i++ {
+		entry := new(UpsertPlayerInfoEntry)
+		entry.ID, err = util.ReadUUID(rd)
+		if err != nil {
+			return err
+		}
+		for _, action := range u.ActionSet {
+			err = entry.decodeAction(c, rd, action)
+			if err != nil {
+				return err
+			}
+		}
+		u.Entries = append(u.Entries, entry)
+	}
+	return nil
+}
+
+func (e *UpsertPlayerInfoEntry) decodeAction(c *proto.PacketContext, rd io.Reader, action UpsertPlayerInfoAction) (err error) {
+	switch action {
+	case AddPlayerUpsertPlayerInfoAction:
+		e.Profile = &profile.GameProfile{ID: e.ID}
+		e.Profile.Name, err = util.ReadStringMax(rd, 16)
+		if err != nil {
+			return err
+		}
+		e.Profile.Properties, err = util.ReadProperties(rd)
+		return err
+	case InitializeChatUpsertPlayerInfoAction:
+		ok, err := util.ReadBool(rd)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			e.ChatSession = nil
+			return nil
+		}
+		e.ChatSession = new(RemoteChatSession)
+		e.ChatSession.ID, err = util.ReadUUID(rd)
+		if err != nil {
+			return err
+		}
+		e.ChatSession.Key, err = crypto.ReadPlayerKey(c.Protocol, rd)
+		return err
+	case UpdateGameModeUpsertPlayerInfoAction:
+		e.GameMode, err = util.ReadVarInt(rd)
+		return err
+	case UpdateListedUpsertPlayerInfoAction:
+		e.Listed, err = util.ReadBool(rd)
+		return err
+	case UpdateLatencyUpsertPlayerInfoAction:
+		e.Latency, err = util.ReadVarInt(rd)
+		return err
+	case UpdateDisplayNameUpsertPlayerInfoAction:
+		e.DisplayName, err = readOptionalComponent(rd, c.Protocol)
+		return err
+	default:
+		return fmt.Errorf("unknown UpsertPlayerInfoAction %d", action)
+	}
+}
+
+// RemovePlayerInfo replaced the remove action of the
+// PlayerListItem packet for removing tab list entries in 1.19.3+.
+type RemovePlayerInfo struct {
+	PlayersToRemove []uuid.UUID
+}
+
+func (r *RemovePlayerInfo) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	err := util.WriteVarInt(wr, len(r.PlayersToRemove))
+	if err != nil {
+		return err
+	}
+	for _, id := range r.PlayersToRemove {
+		err = util.WriteUUID(wr, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RemovePlayerInfo) Decode(_ *proto.PacketContext, rd io.Reader) error {
+	length, err := util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	if length < 0 {
+		return fmt.Errorf("invalid number of players %d", length)
+	}
+	r.PlayersToRemove = nil
+	for i := 0; i < length; i++ {
+		id, err := util.ReadUUID(rd)
+		if err != nil {
+			return err
+		}
+		r.PlayersToRemove = append(r.PlayersToRemove, id)
+	}
+	return nil
+}
+
+var (
+	_ proto.Packet = (*UpsertPlayerInfo)(nil)
+	_ proto.Packet = (*RemovePlayerInfo)(nil)
+)
@@ -15,11 +15,13 @@ type Respawn struct {
 	Difficulty           int16
 	Gamemode             int16
 	LevelType            string         // empty by default
-	ShouldKeepPlayerData bool           // 1.16+
+	ShouldKeepPlayerData bool           // 1.16-1.19.2
+	DataToKeep           byte           // 1.19.3+, bitmask of the player data to keep
 	DimensionInfo        *DimensionInfo // 1.16-1.16.1
 	PreviousGamemode     int16          // 1.16+
 	CurrentDimensionData *DimensionData // 1.16.2+
 	LastDeathPosition    *DeathPosition // 1.19+
+	PortalCooldown       int            // 1.20+
 }
 
 func (r *Respawn) Encode(c *proto.PacketContext, wr io.Writer) (err error) {
@@ -78,9 +80,16 @@ func (r *Respawn) Encode(c *proto.PacketContext, wr io.Writer) (err error) {
 		if err != nil {
 			return err
 		}
-		err = util.WriteBool(wr, r.ShouldKeepPlayerData)
-		if err != nil {
-			return err
+		if c.Protocol.Lower(version.Minecraft_1_19_3) {
+			err = util.WriteBool(wr, r.ShouldKeepPlayerData)
+			if err != nil {
+				return err
+			}
+		} else if c.Protocol.Lower(version.Minecraft_1_20_2) {
+			err = util.WriteByte(wr, r.DataToKeep)
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		err = util.WriteString(wr, r.LevelType)
@@ -96,6 +105,19 @@ func (r *Respawn) Encode(c *proto.PacketContext, wr io.Writer) (err error) {
 			return err
 		}
 	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20) {
+		err = util.WriteVarInt(wr, r.PortalCooldown)
+		if err != nil {
+			return err
+		}
+	}
+	// Moved to the end of the packet in 1.20.2
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		err = util.WriteByte(wr, r.DataToKeep)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -171,9 +193,16 @@ func (r *Respawn) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 			Flat:               flat,
 			DebugType:          debug,
 		}
-		r.ShouldKeepPlayerData, err = util.ReadBool(rd)
-		if err != nil {
-			return err
+		if c.Protocol.Lower(version.Minecraft_1_19_3) {
+			r.ShouldKeepPlayerData, err = util.ReadBool(rd)
+			if err != nil {
+				return err
+			}
+		} else if c.Protocol.Lower(version.Minecraft_1_20_2) {
+			r.DataToKeep, err = util.ReadByte(rd)
+			if err != nil {
+				return err
+			}
 		}
 	} else {
 		r.LevelType, err = util.ReadStringMax(rd, 16)
@@ -187,6 +216,18 @@ func (r *Respawn) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 			return err
 		}
 	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20) {
+		r.PortalCooldown, err = util.ReadVarInt(rd)
+		if err != nil {
+			return err
+		}
+	}
+	if c.Protocol.GreaterEqual(version.Minecraft_1_20_2) {
+		r.DataToKeep, err = util.ReadByte(rd)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -1,7 +1,10 @@
 package packet
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io"
+	"strings"
 
 	"go.minekube.com/common/minecraft/component"
 	"go.minekube.com/gate/pkg/edition/java/proto/util"
@@ -11,37 +14,67 @@ import (
 )
 
 type ServerData struct {
-	Description        component.Component // nil-able
+	Description        component.Component // nil-able, required in 1.19.4+
 	Favicon            favicon.Favicon     // may be empty
-	PreviewsChat       bool
-	SecureChatEnforced bool // Added in 1.19.1
+	PreviewsChat       bool                // Removed in 1.19.3
+	SecureChatEnforced bool                // Added in 1.19.1
 }
 
+// faviconPrefix is the data uri prefix of the raw png favicon sent by 1.19.4+ servers.
+const faviconPrefix = "data:image/png;base64,"
+
 func (s *ServerData) Encode(c *proto.PacketContext, wr io.Writer) error {
-	err := util.WriteBool(wr, s.Description != nil)
-	if err != nil {
-		return err
-	}
-	if s.Description != nil {
-		err = util.WriteComponent(wr, c.Protocol, s.Description)
+	if c.Protocol.GreaterEqual(version.Minecraft_1_19_4) {
+		description := s.Description
+		if description == nil {
+			description = &component.Text{}
+		}
+		err := util.WriteComponent(wr, c.Protocol, description)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := util.WriteBool(wr, s.Description != nil)
 		if err != nil {
 			return err
 		}
+		if s.Description != nil {
+			err = util.WriteComponent(wr, c.Protocol, s.Description)
+			if err != nil {
+				return err
+			}
+		}
 	}
-	err = util.WriteBool(wr, s.Favicon != "")
+	err := util.WriteBool(wr, s.Favicon != "")
 	if err != nil {
 		return err
 	}
 	if s.Favicon != "" {
-		err = util.WriteString(wr, string(s.Favicon))
+		if c.Protocol.GreaterEqual(version.Minecraft_1_19_4) {
+			if !strings.HasPrefix(string(s.Favicon), faviconPrefix) {
+				return fmt.Errorf("favicon must be a %q data uri", faviconPrefix)
+			}
+			b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(s.Favicon), faviconPrefix))
+			if err != nil {
+				return fmt.Errorf("error decoding favicon: %w", err)
+			}
+			err = util.WriteBytes(wr, b)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = util.WriteString(wr, string(s.Favicon))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if c.Protocol.Lower(version.Minecraft_1_19_3) {
+		err = util.WriteBool(wr, s.PreviewsChat)
 		if err != nil {
 			return err
 		}
 	}
-	err = util.WriteBool(wr, s.PreviewsChat)
-	if err != nil {
-		return err
-	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_19_1) {
 		err = util.WriteBool(wr, s.SecureChatEnforced)
 		if err != nil {
@@ -52,30 +85,47 @@ func (s *ServerData) Encode(c *proto.PacketContext, wr io.Writer) error {
 }
 
 func (s *ServerData) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
-	ok, err := util.ReadBool(rd)
-	if err != nil {
-		return err
-	}
-	if ok {
+	if c.Protocol.GreaterEqual(version.Minecraft_1_19_4) {
 		s.Description, err = util.ReadComponent(rd, c.Protocol)
 		if err != nil {
 			return err
 		}
+	} else {
+		ok, err := util.ReadBool(rd)
+		if err != nil {
+			return err
+		}
+		if ok {
+			s.Description, err = util.ReadComponent(rd, c.Protocol)
+			if err != nil {
+				return err
+			}
+		}
 	}
-	ok, err = util.ReadBool(rd)
+	ok, err := util.ReadBool(rd)
 	if err != nil {
 		return err
 	}
 	if ok {
-		fi, err := util.ReadString(rd)
+		if c.Protocol.GreaterEqual(version.Minecraft_1_19_4) {
+			b, err := util.ReadBytes(rd)
+			if err != nil {
+				return err
+			}
+			s.Favicon = favicon.Favicon(faviconPrefix + base64.StdEncoding.EncodeToString(b))
+		} else {
+			fi, err := util.ReadString(rd)
+			if err != nil {
+				return err
+			}
+			s.Favicon = favicon.Favicon(fi)
+		}
+	}
+	if c.Protocol.Lower(version.Minecraft_1_19_3) {
+		s.PreviewsChat, err = util.ReadBool(rd)
 		if err != nil {
 			return err
 		}
-		s.Favicon = favicon.Favicon(fi)
-	}
-	s.PreviewsChat, err = util.ReadBool(rd)
-	if err != nil {
-		return err
 	}
 	if c.Protocol.GreaterEqual(version.Minecraft_1_19_1) {
 		s.SecureChatEnforced, err = util.ReadBool(rd)
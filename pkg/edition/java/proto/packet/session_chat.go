@@ -0,0 +1,229 @@
+package packet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// Chat sessions replaced the player key based chat signing in 1.19.3.
+// The following packets are only used by 1.19.3+ clients.
+
+const (
+	// messageSignatureLength is the fixed length of a chat message signature.
+	messageSignatureLength = 256
+	// lastSeenMessagesBytes is the number of bytes of the 20 bit last seen messages bitset.
+	lastSeenMessagesBytes = 3
+)
+
+// LastSeenMessages acknowledges the last chat messages the client has seen.
+type LastSeenMessages struct {
+	Offset       int
+	Acknowledged [lastSeenMessagesBytes]byte // bitset of the last 20 messages
+}
+
+func (l *LastSeenMessages) encode(wr io.Writer) error {
+	err := util.WriteVarInt(wr, l.Offset)
+	if err != nil {
+		return err
+	}
+	return util.WriteRawBytes(wr, l.Acknowledged[:])
+}
+
+func (l *LastSeenMessages) decode(rd io.Reader) (err error) {
+	l.Offset, err = util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	_, err = io.ReadFull(rd, l.Acknowledged[:])
+	return err
+}
+
+func writeMessageSignature(wr io.Writer, signature []byte) error {
+	if len(signature) != messageSignatureLength {
+		return fmt.Errorf("%w: signature must be %d bytes but was %d",
+			errInvalidSignature, messageSignatureLength, len(signature))
+	}
+	return util.WriteRawBytes(wr, signature)
+}
+
+func readMessageSignature(rd io.Reader) ([]byte, error) {
+	signature := make([]byte, messageSignatureLength)
+	_, err := io.ReadFull(rd, signature)
+	return signature, err
+}
+
+// SessionPlayerChat is a chat message sent by a 1.19.3+ client.
+type SessionPlayerChat struct {
+	Message          string
+	Timestamp        time.Time
+	Salt             int64
+	Signed           bool
+	Signature        []byte // 256 bytes if Signed
+	LastSeenMessages LastSeenMessages
+}
+
+func (s *SessionPlayerChat) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	err := util.WriteString(wr, s.Message)
+	if err != nil {
+		return err
+	}
+	err = util.WriteInt64(wr, s.Timestamp.UnixMilli())
+	if err != nil {
+		return err
+	}
+	err = util.WriteInt64(wr, s.Salt)
+	if err != nil {
+		return err
+	}
+	err = util.WriteBool(wr, s.Signed)
+	if err != nil {
+		return err
+	}
+	if s.Signed {
+		err = writeMessageSignature(wr, s.Signature)
+		if err != nil {
+			return err
+		}
+	}
+	return s.LastSeenMessages.encode(wr)
+}
+
+func (s *SessionPlayerChat) Decode(_ *proto.PacketContext, rd io.Reader) (err error) {
+	s.Message, err = util.ReadStringMax(rd, MaxServerBoundMessageLength)
+	if err != nil {
+		return err
+	}
+	s.Timestamp, err = util.ReadUnixMilli(rd)
+	if err != nil {
+		return err
+	}
+	s.Salt, err = util.ReadInt64(rd)
+	if err != nil {
+		return err
+	}
+	s.Signed, err = util.ReadBool(rd)
+	if err != nil {
+		return err
+	}
+	if s.Signed {
+		s.Signature, err = readMessageSignature(rd)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.Signature = nil
+	}
+	return s.LastSeenMessages.decode(rd)
+}
+
+// ArgumentSignature is the signature of a signable command argument.
+type ArgumentSignature struct {
+	Name      string
+	Signature []byte // 256 bytes
+}
+
+// SessionPlayerCommand is a command sent by a 1.19.3+ client.
+type SessionPlayerCommand struct {
+	Command            string
+	Timestamp          time.Time
+	Salt               int64
+	ArgumentSignatures []ArgumentSignature
+	LastSeenMessages   LastSeenMessages
+}
+
+// Signed returns true if the command contains signed arguments.
+func (s *SessionPlayerCommand) Signed() bool {
+	return len(s.ArgumentSignatures) != 0
+}
+
+func (s *SessionPlayerCommand) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	err := util.WriteString(wr, s.Command)
+	if err != nil {
+		return err
+	}
+	err = util.WriteInt64(wr, s.Timestamp.UnixMilli())
+	if err != nil {
+		return err
+	}
+	err = util.WriteInt64(wr, s.Salt)
+	if err != nil {
+		return err
+	}
+	if len(s.ArgumentSignatures) > maxNumArguments {
+		return fmt.Errorf("%w: max is %d but was %d", errLimitsViolation, maxNumArguments, len(s.ArgumentSignatures))
+	}
+	err = util.WriteVarInt(wr, len(s.ArgumentSignatures))
+	if err != nil {
+		return err
+	}
+	for _, a := range s.ArgumentSignatures {
+		err = util.WriteString(wr, a.Name)
+		if err != nil {
+			return err
+		}
+		err = writeMessageSignature(wr, a.Signature)
+		if err != nil {
+			return err
+		}
+	}
+	return s.LastSeenMessages.encode(wr)
+}
+
+func (s *SessionPlayerCommand) Decode(_ *proto.PacketContext, rd io.Reader) (err error) {
+	s.Command, err = util.ReadStringMax(rd, MaxServerBoundMessageLength)
+	if err != nil {
+		return err
+	}
+	s.Timestamp, err = util.ReadUnixMilli(rd)
+	if err != nil {
+		return err
+	}
+	s.Salt, err = util.ReadInt64(rd)
+	if err != nil {
+		return err
+	}
+	size, err := util.ReadVarInt(rd)
+	if err != nil {
+		return err
+	}
+	if size < 0 || size > maxNumArguments {
+		return fmt.Errorf("%w: max is %d but was %d", errLimitsViolation, maxNumArguments, size)
+	}
+	s.ArgumentSignatures = make([]ArgumentSignature, size)
+	for i := range s.ArgumentSignatures {
+		s.ArgumentSignatures[i].Name, err = util.ReadStringMax(rd, maxLengthArguments)
+		if err != nil {
+			return err
+		}
+		s.ArgumentSignatures[i].Signature, err = readMessageSignature(rd)
+		if err != nil {
+			return err
+		}
+	}
+	return s.LastSeenMessages.decode(rd)
+}
+
+// ChatAcknowledgement is sent by 1.19.3+ clients to acknowledge
+// received chat messages without sending a message.
+type ChatAcknowledgement struct {
+	Offset int
+}
+
+func (c *ChatAcknowledgement) Encode(_ *proto.PacketContext, wr io.Writer) error {
+	return util.WriteVarInt(wr, c.Offset)
+}
+
+func (c *ChatAcknowledgement) Decode(_ *proto.PacketContext, rd io.Reader) (err error) {
+	c.Offset, err = util.ReadVarInt(rd)
+	return
+}
+
+var (
+	_ proto.Packet = (*SessionPlayerChat)(nil)
+	_ proto.Packet = (*SessionPlayerCommand)(nil)
+	_ proto.Packet = (*ChatAcknowledgement)(nil)
+)
@@ -28,7 +28,7 @@ func (h *HeaderAndFooter) Encode(c *proto.PacketContext, wr io.Writer) error {
 	return util.WriteString(wr, h.Footer)
 }
 
-// we never read this packet
+// read when the proxy handles the tab list header and footer sent by backend servers
 func (h *HeaderAndFooter) Decode(c *proto.PacketContext, rd io.Reader) (err error) {
 	h.Header, err = util.ReadString(rd)
 	if err != nil {
@@ -225,6 +225,8 @@ func (s State) String() string {
 		return "Login"
 	case PlayState:
 		return "Play"
+	case ConfigState:
+		return "Config"
 	}
 	return "UnknownState"
 }
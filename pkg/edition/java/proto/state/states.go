@@ -3,6 +3,7 @@ package state
 import (
 	p "go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/bossbar"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/config"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/title"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
@@ -17,6 +18,7 @@ const (
 	StatusState
 	LoginState
 	PlayState
+	ConfigState // 1.20.2+
 )
 
 // The registries storing the packets for a connection state.
@@ -24,6 +26,7 @@ var (
 	Handshake = NewRegistry(HandshakeState)
 	Status    = NewRegistry(StatusState)
 	Login     = NewRegistry(LoginState)
+	Config    = NewRegistry(ConfigState)
 	Play      = NewRegistry(PlayState)
 )
 
@@ -47,6 +50,8 @@ func init() {
 		m(0x01, version.Minecraft_1_7_2))
 	Login.ServerBound.Register(&p.LoginPluginResponse{},
 		m(0x02, version.Minecraft_1_7_2))
+	Login.ServerBound.Register(&p.LoginAcknowledged{},
+		m(0x03, version.Minecraft_1_20_2))
 
 	Login.ClientBound.Register(&p.Disconnect{},
 		m(0x00, version.Minecraft_1_7_2))
@@ -59,6 +64,31 @@ func init() {
 	Login.ClientBound.Register(&p.LoginPluginMessage{},
 		m(0x04, version.Minecraft_1_13))
 
+	Config.ServerBound.Fallback = false
+	Config.ClientBound.Fallback = false
+
+	Config.ServerBound.Register(&p.ClientSettings{},
+		m(0x00, version.Minecraft_1_20_2))
+	Config.ServerBound.Register(&plugin.Message{},
+		m(0x01, version.Minecraft_1_20_2))
+	Config.ServerBound.Register(&config.FinishedUpdate{},
+		m(0x02, version.Minecraft_1_20_2))
+	Config.ServerBound.Register(&p.KeepAlive{},
+		m(0x03, version.Minecraft_1_20_2))
+	Config.ServerBound.Register(&p.ResourcePackResponse{},
+		m(0x05, version.Minecraft_1_20_2))
+
+	Config.ClientBound.Register(&plugin.Message{},
+		m(0x00, version.Minecraft_1_20_2))
+	Config.ClientBound.Register(&p.Disconnect{},
+		m(0x01, version.Minecraft_1_20_2))
+	Config.ClientBound.Register(&config.FinishedUpdate{},
+		m(0x02, version.Minecraft_1_20_2))
+	Config.ClientBound.Register(&p.KeepAlive{},
+		m(0x03, version.Minecraft_1_20_2))
+	Config.ClientBound.Register(&p.ResourcePackRequest{},
+		m(0x06, version.Minecraft_1_20_2))
+
 	Play.ServerBound.Fallback = false
 	Play.ClientBound.Fallback = false
 
@@ -73,6 +103,9 @@ func init() {
 		m(0x0F, version.Minecraft_1_17),
 		m(0x11, version.Minecraft_1_19),
 		m(0x12, version.Minecraft_1_19_1),
+		m(0x11, version.Minecraft_1_19_3),
+		m(0x12, version.Minecraft_1_19_4),
+		m(0x14, version.Minecraft_1_20_2),
 	)
 	Play.ServerBound.Register(&plugin.Message{},
 		m(0x17, version.Minecraft_1_7_2),
@@ -84,6 +117,9 @@ func init() {
 		m(0x0A, version.Minecraft_1_17),
 		m(0x0C, version.Minecraft_1_19),
 		m(0x0D, version.Minecraft_1_19_1),
+		m(0x0C, version.Minecraft_1_19_3),
+		m(0x0D, version.Minecraft_1_19_4),
+		m(0x0F, version.Minecraft_1_20_2),
 	)
 	Play.ServerBound.Register(&p.ClientSettings{},
 		m(0x15, version.Minecraft_1_7_2),
@@ -93,6 +129,9 @@ func init() {
 		m(0x05, version.Minecraft_1_14),
 		m(0x07, version.Minecraft_1_19),
 		m(0x08, version.Minecraft_1_19_1),
+		m(0x07, version.Minecraft_1_19_3),
+		m(0x08, version.Minecraft_1_19_4),
+		m(0x09, version.Minecraft_1_20_2),
 	)
 	Play.ServerBound.Register(&p.LegacyChat{},
 		m(0x01, version.Minecraft_1_7_2),
@@ -103,11 +142,20 @@ func init() {
 	)
 	Play.ServerBound.Register(&p.PlayerCommand{},
 		m(0x03, version.Minecraft_1_19),
-		m(0x04, version.Minecraft_1_19_1),
+		ml(0x04, version.Minecraft_1_19_1, version.Minecraft_1_19_1),
 	)
 	Play.ServerBound.Register(&p.PlayerChat{},
 		m(0x04, version.Minecraft_1_19),
-		m(0x05, version.Minecraft_1_19_1),
+		ml(0x05, version.Minecraft_1_19_1, version.Minecraft_1_19_1),
+	)
+	Play.ServerBound.Register(&p.ChatAcknowledgement{},
+		m(0x03, version.Minecraft_1_19_3),
+	)
+	Play.ServerBound.Register(&p.SessionPlayerCommand{},
+		m(0x04, version.Minecraft_1_19_3),
+	)
+	Play.ServerBound.Register(&p.SessionPlayerChat{},
+		m(0x05, version.Minecraft_1_19_3),
 	)
 	Play.ServerBound.Register(&p.TabCompleteRequest{},
 		m(0x14, version.Minecraft_1_7_2),
@@ -118,6 +166,9 @@ func init() {
 		m(0x06, version.Minecraft_1_14),
 		m(0x08, version.Minecraft_1_19),
 		m(0x09, version.Minecraft_1_19_1),
+		m(0x08, version.Minecraft_1_19_3),
+		m(0x09, version.Minecraft_1_19_4),
+		m(0x0A, version.Minecraft_1_20_2),
 	)
 	Play.ServerBound.Register(&p.ResourcePackResponse{},
 		m(0x19, version.Minecraft_1_8),
@@ -129,6 +180,10 @@ func init() {
 		m(0x21, version.Minecraft_1_16_2),
 		m(0x23, version.Minecraft_1_19),
 		m(0x24, version.Minecraft_1_19_1),
+		m(0x27, version.Minecraft_1_20_2),
+	)
+	Play.ServerBound.Register(&config.FinishedUpdate{},
+		m(0x0B, version.Minecraft_1_20_2),
 	)
 	// coming soon...
 
@@ -143,6 +198,9 @@ func init() {
 		m(0x21, version.Minecraft_1_17),
 		m(0x1E, version.Minecraft_1_19),
 		m(0x20, version.Minecraft_1_19_1),
+		m(0x1F, version.Minecraft_1_19_3),
+		m(0x23, version.Minecraft_1_19_4),
+		m(0x24, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.JoinGame{},
 		m(0x01, version.Minecraft_1_7_2),
@@ -155,6 +213,9 @@ func init() {
 		m(0x26, version.Minecraft_1_17),
 		m(0x23, version.Minecraft_1_19),
 		m(0x25, version.Minecraft_1_19_1),
+		m(0x24, version.Minecraft_1_19_3),
+		m(0x28, version.Minecraft_1_19_4),
+		m(0x29, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.Respawn{},
 		m(0x07, version.Minecraft_1_7_2),
@@ -169,6 +230,9 @@ func init() {
 		m(0x3D, version.Minecraft_1_17),
 		m(0x3B, version.Minecraft_1_19),
 		m(0x3E, version.Minecraft_1_19_1),
+		m(0x3D, version.Minecraft_1_19_3),
+		m(0x41, version.Minecraft_1_19_4),
+		m(0x43, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.Disconnect{},
 		m(0x40, version.Minecraft_1_7_2),
@@ -181,6 +245,9 @@ func init() {
 		m(0x1A, version.Minecraft_1_17),
 		m(0x17, version.Minecraft_1_19),
 		m(0x19, version.Minecraft_1_19_1),
+		m(0x17, version.Minecraft_1_19_3),
+		m(0x1A, version.Minecraft_1_19_4),
+		m(0x1B, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&bossbar.BossBar{},
 		m(0x0C, version.Minecraft_1_9),
@@ -188,6 +255,8 @@ func init() {
 		m(0x0C, version.Minecraft_1_16),
 		m(0x0D, version.Minecraft_1_17),
 		m(0x0A, version.Minecraft_1_19),
+		m(0x0B, version.Minecraft_1_19_4),
+		m(0x0A, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.LegacyChat{},
 		m(0x02, version.Minecraft_1_7_2),
@@ -211,6 +280,9 @@ func init() {
 		m(0x5F, version.Minecraft_1_18),
 		m(0x60, version.Minecraft_1_19),
 		m(0x63, version.Minecraft_1_19_1),
+		m(0x61, version.Minecraft_1_19_3),
+		m(0x65, version.Minecraft_1_19_4),
+		m(0x68, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.PlayerListItem{},
 		m(0x38, version.Minecraft_1_7_2),
@@ -223,7 +295,17 @@ func init() {
 		m(0x32, version.Minecraft_1_16_2),
 		m(0x36, version.Minecraft_1_17),
 		m(0x34, version.Minecraft_1_19),
-		m(0x37, version.Minecraft_1_19_1),
+		ml(0x37, version.Minecraft_1_19_1, version.Minecraft_1_19_1),
+	)
+	Play.ClientBound.Register(&p.RemovePlayerInfo{},
+		m(0x35, version.Minecraft_1_19_3),
+		m(0x39, version.Minecraft_1_19_4),
+		m(0x3B, version.Minecraft_1_20_2),
+	)
+	Play.ClientBound.Register(&p.UpsertPlayerInfo{},
+		m(0x36, version.Minecraft_1_19_3),
+		m(0x3A, version.Minecraft_1_19_4),
+		m(0x3C, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&title.Legacy{},
 		m(0x45, version.Minecraft_1_8),
@@ -239,25 +321,40 @@ func init() {
 		m(0x57, version.Minecraft_1_17),
 		m(0x58, version.Minecraft_1_18),
 		m(0x5B, version.Minecraft_1_19_1),
+		m(0x59, version.Minecraft_1_19_3),
+		m(0x5D, version.Minecraft_1_19_4),
+		m(0x5F, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&title.Text{},
 		m(0x59, version.Minecraft_1_17),
 		m(0x5A, version.Minecraft_1_18),
 		m(0x5D, version.Minecraft_1_19_1),
+		m(0x5B, version.Minecraft_1_19_3),
+		m(0x5F, version.Minecraft_1_19_4),
+		m(0x61, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&title.Actionbar{},
 		m(0x41, version.Minecraft_1_17),
 		m(0x40, version.Minecraft_1_19),
 		m(0x43, version.Minecraft_1_19_1),
+		m(0x42, version.Minecraft_1_19_3),
+		m(0x46, version.Minecraft_1_19_4),
+		m(0x48, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&title.Times{},
 		m(0x5A, version.Minecraft_1_17),
 		m(0x5B, version.Minecraft_1_18),
 		m(0x5E, version.Minecraft_1_19_1),
+		m(0x5C, version.Minecraft_1_19_3),
+		m(0x60, version.Minecraft_1_19_4),
+		m(0x62, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&title.Clear{},
 		m(0x10, version.Minecraft_1_17),
 		m(0x0D, version.Minecraft_1_19),
+		m(0x0C, version.Minecraft_1_19_3),
+		m(0x0E, version.Minecraft_1_19_4),
+		m(0x0F, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&plugin.Message{},
 		m(0x3F, version.Minecraft_1_7_2),
@@ -270,6 +367,9 @@ func init() {
 		m(0x18, version.Minecraft_1_17),
 		m(0x15, version.Minecraft_1_19),
 		m(0x16, version.Minecraft_1_19_1),
+		m(0x15, version.Minecraft_1_19_3),
+		m(0x17, version.Minecraft_1_19_4),
+		m(0x18, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.ResourcePackRequest{},
 		m(0x48, version.Minecraft_1_8),
@@ -284,6 +384,9 @@ func init() {
 		m(0x3C, version.Minecraft_1_17),
 		m(0x3A, version.Minecraft_1_19),
 		m(0x3D, version.Minecraft_1_19_1),
+		m(0x3C, version.Minecraft_1_19_3),
+		m(0x40, version.Minecraft_1_19_4),
+		m(0x42, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.TabCompleteResponse{},
 		m(0x3A, version.Minecraft_1_7_2),
@@ -294,6 +397,9 @@ func init() {
 		m(0x0F, version.Minecraft_1_16_2),
 		m(0x11, version.Minecraft_1_17),
 		m(0x0E, version.Minecraft_1_19),
+		m(0x0D, version.Minecraft_1_19_3),
+		m(0x0F, version.Minecraft_1_19_4),
+		m(0x10, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.AvailableCommands{},
 		m(0x11, version.Minecraft_1_13),
@@ -302,17 +408,37 @@ func init() {
 		m(0x10, version.Minecraft_1_16_2),
 		m(0x12, version.Minecraft_1_17),
 		m(0x0F, version.Minecraft_1_19),
+		m(0x0E, version.Minecraft_1_19_3),
+		m(0x10, version.Minecraft_1_19_4),
+		m(0x11, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.SystemChat{},
 		m(0x5F, version.Minecraft_1_19),
 		m(0x62, version.Minecraft_1_19_1),
+		m(0x60, version.Minecraft_1_19_3),
+		m(0x64, version.Minecraft_1_19_4),
+		m(0x67, version.Minecraft_1_20_2),
+	)
+	Play.ClientBound.Register(&p.DisguisedChat{},
+		m(0x18, version.Minecraft_1_19_3),
+		m(0x1B, version.Minecraft_1_19_4),
+		m(0x1C, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.PlayerChatCompletion{},
 		m(0x15, version.Minecraft_1_19_1),
+		m(0x14, version.Minecraft_1_19_3),
+		m(0x16, version.Minecraft_1_19_4),
+		m(0x17, version.Minecraft_1_20_2),
 	)
 	Play.ClientBound.Register(&p.ServerData{},
 		m(0x3F, version.Minecraft_1_19),
 		m(0x42, version.Minecraft_1_19_1),
+		m(0x41, version.Minecraft_1_19_3),
+		m(0x45, version.Minecraft_1_19_4),
+		m(0x47, version.Minecraft_1_20_2),
+	)
+	Play.ClientBound.Register(&config.StartUpdate{},
+		m(0x65, version.Minecraft_1_20_2),
 	)
 	// coming soon...
 	// BossBar
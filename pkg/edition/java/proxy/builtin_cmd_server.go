@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"sort"
-	"time"
 
 	"go.minekube.com/brigodier"
 	. "go.minekube.com/common/minecraft/color"
@@ -40,8 +39,7 @@ func newServerCmd(proxy *Proxy) brigodier.LiteralNodeBuilder {
 						Content: fmt.Sprintf("Server %q doesn't exist.", name)})
 				}
 
-				ctx, cancel := context.WithTimeout(context.Background(),
-					time.Millisecond*time.Duration(proxy.cfg.ConnectionTimeout))
+				ctx, cancel := withConnectionTimeout(context.Background(), proxy.config(), rs)
 				defer cancel()
 				player.CreateConnectionRequest(rs).ConnectWithIndication(ctx)
 				return nil
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"net"
-	"time"
 
 	"go.minekube.com/common/minecraft/component"
 	"go.minekube.com/gate/pkg/edition/java/netmc"
@@ -68,8 +67,7 @@ func (s *bungeeServer) Connect(player bungeecord.Player) {
 	if p == nil {
 		return
 	}
-	timeout := time.Duration(s.proxy.config().ConnectionTimeout) * time.Millisecond
-	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	ctx, cancel := withConnectionTimeout(context.TODO(), s.proxy.config(), s.s)
 	defer cancel()
 	_ = p.CreateConnectionRequest(s.s).ConnectWithIndication(ctx)
 }
@@ -4,6 +4,7 @@ import (
 	"go.minekube.com/brigodier"
 	"go.minekube.com/common/minecraft/component"
 	"go.minekube.com/gate/pkg/command"
+	"go.minekube.com/gate/pkg/edition/java/config"
 	"go.minekube.com/gate/pkg/edition/java/modinfo"
 	"go.minekube.com/gate/pkg/edition/java/ping"
 	"go.minekube.com/gate/pkg/edition/java/profile"
@@ -767,13 +768,15 @@ func (c *CommandExecuteEvent) Forward() bool {
 //
 //
 
-// TabCompleteEvent is fired after a tab complete response is sent by the remote server,
-// for clients on1.12.2 and below. You have the opportunity to modify the response sent
-// to the remote player.
+// TabCompleteEvent is fired after a tab complete response is sent by the remote server
+// for clients on 1.12.2 and below, or the proxy completed one of its commands.
+// The suggestions of the remote server and of proxy commands are already merged.
+// You have the opportunity to modify the response sent to the remote player.
 type TabCompleteEvent struct {
 	player         Player
 	partialMessage string
 	suggestions    []string
+	tooltips       map[string]component.Component // suggestion:tooltip
 }
 
 // Player returns the player requesting the tab completion.
@@ -796,6 +799,24 @@ func (t *TabCompleteEvent) PartialMessage() string {
 	return t.partialMessage
 }
 
+// Tooltip returns the tooltip of a suggestion, may be nil.
+// Tooltips are only shown to clients on 1.13 and above.
+func (t *TabCompleteEvent) Tooltip(suggestion string) component.Component {
+	return t.tooltips[suggestion]
+}
+
+// SetTooltip sets the tooltip of a suggestion, nil removes it.
+func (t *TabCompleteEvent) SetTooltip(suggestion string, tooltip component.Component) {
+	if tooltip == nil {
+		delete(t.tooltips, suggestion)
+		return
+	}
+	if t.tooltips == nil {
+		t.tooltips = map[string]component.Component{}
+	}
+	t.tooltips[suggestion] = tooltip
+}
+
 //
 //
 //
@@ -921,7 +942,28 @@ func (e *ServerResourcePackSendEvent) SetProvidedResourcePack(pack ResourcePackI
 	e.providedResourcePack = pack
 }
 
-// TODO PlayerClientBrandEvent
+//
+//
+//
+//
+
+// PlayerClientBrandEvent is fired when a Player sent the brand of its client
+// for the first time, e.g. "vanilla", "fabric", "forge" or "lunarclient:...".
+// The proxy will not wait on this event to finish firing.
+type PlayerClientBrandEvent struct {
+	player Player
+	brand  string
+}
+
+// Player returns the player that sent the client brand.
+func (e *PlayerClientBrandEvent) Player() Player {
+	return e.player
+}
+
+// Brand returns the brand of the player's client.
+func (e *PlayerClientBrandEvent) Brand() string {
+	return e.brand
+}
 
 //
 //
@@ -1032,3 +1074,56 @@ type ReadyEvent struct{}
 // Subscribe to this event to gracefully stop any subtasks,
 // such as plugin dependencies.
 type ShutdownEvent struct{}
+
+//
+//
+//
+//
+
+// ConfigUpdateEvent is fired after the proxy reloaded its config using Proxy.Reload.
+// Settings requiring a restart keep their previous values in the new config.
+type ConfigUpdateEvent struct {
+	config     *config.Config
+	prevConfig *config.Config
+}
+
+// Config returns the config now used by the proxy.
+// It must not be modified.
+func (e *ConfigUpdateEvent) Config() *config.Config {
+	return e.config
+}
+
+// PrevConfig returns the config used by the proxy before the reload.
+// It must not be modified.
+func (e *ConfigUpdateEvent) PrevConfig() *config.Config {
+	return e.prevConfig
+}
+
+//
+//
+//
+//
+
+// ServerStatusChangeEvent is fired when a health check found a registered server
+// online or offline for the first time or the server went online or offline.
+type ServerStatusChangeEvent struct {
+	server     RegisteredServer
+	prevStatus ServerStatus
+	status     ServerStatus
+}
+
+// Server returns the server whose status changed.
+func (e *ServerStatusChangeEvent) Server() RegisteredServer {
+	return e.server
+}
+
+// PreviousStatus returns the status of the server before the health check.
+// It is a zero ServerStatus if the server was not checked before.
+func (e *ServerStatusChangeEvent) PreviousStatus() ServerStatus {
+	return e.prevStatus
+}
+
+// Status returns the status of the server determined by the health check.
+func (e *ServerStatusChangeEvent) Status() ServerStatus {
+	return e.status
+}
@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/edition/java/ping"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+)
+
+// ServerStatus is the status of a registered server as determined by health checks.
+type ServerStatus struct {
+	Online      bool                // Whether the server responded to the last health check.
+	LastCheck   time.Time           // The time of the last health check, zero if not checked yet.
+	Latency     time.Duration       // The round-trip time of the last health check ping.
+	Version     ping.Version        // The version reported by the server.
+	Players     *ping.Players       // The player counts reported by the server, may be nil.
+	Description component.Component // The motd reported by the server, may be nil.
+	Err         error               // The error of the last health check if the server is offline.
+}
+
+// Checked returns true if the server was health checked at least once.
+func (s ServerStatus) Checked() bool {
+	return !s.LastCheck.IsZero()
+}
+
+// offline returns true if the server did not respond to the last health check.
+func (s ServerStatus) offline() bool {
+	return s.Checked() && !s.Online
+}
+
+// ServerStatus returns the status of the registered server by name as determined
+// by the last health check, or false if no such server is registered.
+func (p *Proxy) ServerStatus(name string) (ServerStatus, bool) {
+	s := p.server(name)
+	if s == nil {
+		return ServerStatus{}, false
+	}
+	return s.Status(), true
+}
+
+// healthCheckIdleInterval is the interval to re-read the cfg while health checks are disabled.
+const healthCheckIdleInterval = 10 * time.Second
+
+// runHealthChecks pings all registered servers in the configured interval until stop is closed.
+// The health check cfg is read every round so that config reloads apply.
+func (p *Proxy) runHealthChecks(stop <-chan struct{}) {
+	for {
+		cfg := p.config().HealthCheck
+		interval := healthCheckIdleInterval
+		if cfg.Enabled {
+			p.checkServers(time.Duration(cfg.Timeout) * time.Millisecond)
+			interval = time.Duration(cfg.Interval) * time.Millisecond
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkServers pings all registered servers at once and waits for them to respond.
+func (p *Proxy) checkServers(timeout time.Duration) {
+	p.muS.RLock()
+	servers := make([]*registeredServer, 0, len(p.servers))
+	for _, s := range p.servers {
+		servers = append(servers, s)
+	}
+	p.muS.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(len(servers))
+	for _, s := range servers {
+		go func(s *registeredServer) {
+			defer wg.Done()
+			p.checkServer(ctx, s)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// checkServer pings the server, updates its status and fires
+// a ServerStatusChangeEvent if the server went online or offline.
+func (p *Proxy) checkServer(ctx context.Context, s *registeredServer) {
+	name := s.ServerInfo().Name()
+	start := time.Now()
+	pong, err := pingServer(ctx, s, version.MaximumVersion.Protocol, nil,
+		p.config().ProxyProtocolFor(name), p.log)
+
+	status := ServerStatus{LastCheck: time.Now()}
+	if err != nil {
+		status.Err = err
+	} else {
+		status.Online = true
+		status.Latency = status.LastCheck.Sub(start)
+		status.Version = pong.Version
+		status.Players = pong.Players
+		status.Description = pong.Description
+	}
+	prev := s.setStatus(status)
+	if prev.Checked() && prev.Online == status.Online {
+		return
+	}
+	if p.server(name) != s {
+		return // unregistered in the meantime
+	}
+
+	if status.Online {
+		p.log.Info("Server is online", "server", name, "latency", status.Latency.String())
+	} else {
+		p.log.Info("Server is offline", "server", name, "error", err)
+	}
+	p.event.FireParallel(&ServerStatusChangeEvent{
+		server:     s,
+		prevStatus: prev,
+		status:     status,
+	})
+}
@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/codec"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/state"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+func newTestProxy(t *testing.T, cfg config.Config) *Proxy {
+	p, err := New(Options{Config: &cfg, EventMgr: event.New(logr.Discard())})
+	require.NoError(t, err)
+	return p
+}
+
+// serveStatus answers the status requests of the listener's connections.
+func serveStatus(ln net.Listener, status string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			dec := codec.NewDecoder(conn, proto.ServerBound, logr.Discard())
+			enc := codec.NewEncoder(conn, proto.ClientBound, logr.Discard())
+			enc.SetProtocol(version.MaximumVersion.Protocol)
+			dec.SetProtocol(version.MaximumVersion.Protocol)
+			if _, err := dec.Decode(); err != nil { // handshake
+				return
+			}
+			dec.SetState(state.Status)
+			enc.SetState(state.Status)
+			if _, err := dec.Decode(); err != nil { // status request
+				return
+			}
+			_, _ = enc.WritePacket(&packet.StatusResponse{Status: status})
+		}()
+	}
+}
+
+func TestProxy_checkServer(t *testing.T) {
+	p := newTestProxy(t, config.DefaultConfig)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go serveStatus(ln, `{"version":{"name":"test","protocol":763},"players":{"max":10,"online":1},"description":{"text":"hi"}}`)
+
+	rs, err := p.Register(NewServerInfo("lobby", ln.Addr()))
+	require.NoError(t, err)
+	s := rs.(*registeredServer)
+
+	events := make(chan *ServerStatusChangeEvent, 3)
+	p.event.Subscribe(&ServerStatusChangeEvent{}, 0, func(e event.Event) {
+		events <- e.(*ServerStatusChangeEvent)
+	})
+	check := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		p.checkServer(ctx, s)
+		p.event.Wait()
+	}
+
+	status, ok := p.ServerStatus("Lobby")
+	require.True(t, ok)
+	require.False(t, status.Checked())
+
+	// unchecked -> online
+	check()
+	require.Len(t, events, 1)
+	e := <-events
+	require.Equal(t, rs, e.Server())
+	require.False(t, e.PreviousStatus().Checked())
+	require.True(t, e.Status().Online)
+	require.Equal(t, "test", e.Status().Version.Name)
+	require.Equal(t, 1, e.Status().Players.Online)
+	require.Equal(t, e.Status(), rs.Status())
+
+	// online -> online
+	check()
+	require.Len(t, events, 0)
+	require.True(t, s.Status().Online)
+
+	// online -> offline
+	require.NoError(t, ln.Close())
+	check()
+	require.Len(t, events, 1)
+	e = <-events
+	require.True(t, e.PreviousStatus().Online)
+	require.False(t, e.Status().Online)
+	require.Error(t, e.Status().Err)
+	require.True(t, s.Status().offline())
+
+	_, ok = p.ServerStatus("unknown")
+	require.False(t, ok)
+}
+
+func TestConnectedPlayer_nextServerToTry(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.HealthCheck.Enabled = true
+	cfg.Try = []string{"a", "b", "c"}
+	p := newTestProxy(t, cfg)
+	servers := map[string]*registeredServer{}
+	for _, name := range cfg.Try {
+		rs, err := p.Register(NewServerInfo(name, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25566}))
+		require.NoError(t, err)
+		servers[name] = rs.(*registeredServer)
+	}
+	newPlayer := func() *connectedPlayer {
+		return &connectedPlayer{sessionHandlerDeps: &sessionHandlerDeps{proxy: p, configProvider: p}}
+	}
+	offline := ServerStatus{LastCheck: time.Now()}
+
+	// Offline servers are skipped
+	servers["a"].setStatus(offline)
+	require.Equal(t, servers["b"], newPlayer().nextServerToTry(nil))
+
+	// Falls back to offline servers if all are offline
+	servers["b"].setStatus(offline)
+	servers["c"].setStatus(offline)
+	require.Equal(t, servers["a"], newPlayer().nextServerToTry(nil))
+	require.Equal(t, servers["b"], newPlayer().nextServerToTry(servers["a"]))
+}
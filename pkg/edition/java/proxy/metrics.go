@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	playersOnline = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gate_java_players_online",
+		Help: "Players connected to the proxy.",
+	})
+	serverPlayersOnline = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gate_java_server_players_online",
+		Help: "Players connected to a registered server.",
+	}, []string{"server"})
+	connectionAttempts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gate_java_connection_attempts_total",
+		Help: "Connections accepted by the proxy listener.",
+	})
+	quotaRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_quota_rejections_total",
+		Help: "Connections or logins rejected by a rate limit quota.",
+	}, []string{"quota"})
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_logins_total",
+		Help: "Player logins by result and the reason of failures.",
+	}, []string{"result", "reason"})
+	serverSwitchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "gate_java_server_switch_duration_seconds",
+		Help: "Duration of successful connections of players to a server, including server switches.",
+	}, []string{"server"})
+	backendConnectErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gate_java_backend_connect_errors_total",
+		Help: "Errors connecting players to a server.",
+	}, []string{"server"})
+)
+
+// Label values of the metrics.
+const (
+	quotaConnections = "connections"
+	quotaLogins      = "logins"
+
+	loginSucceededResult = "success"
+	loginFailedResult    = "failure"
+
+	// Reasons of failed logins
+	loginInvalidKey       = "invalid_key"
+	loginInvalidName      = "invalid_name"
+	loginPreLoginDenied   = "pre_login_denied"
+	loginAuthFailed       = "auth_failed"
+	loginOfflineMode      = "offline_mode"
+	loginDenied           = "login_denied"
+	loginAlreadyConnected = "already_connected"
+	loginInternalError    = "internal_error"
+)
+
+// loginFailed counts a failed login by reason.
+func loginFailed(reason string) {
+	logins.WithLabelValues(loginFailedResult, reason).Inc()
+}
+
+// loginSucceeded counts a successful login.
+func loginSucceeded() {
+	logins.WithLabelValues(loginSucceededResult, "").Inc()
+}
@@ -0,0 +1,23 @@
+package proxy
+
+import (
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+)
+
+func TestProxy_Unregister_serverPlayersOnline(t *testing.T) {
+	p := newTestProxy(t, config.DefaultConfig)
+	info := NewServerInfo("metrics", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25566})
+	before := testutil.CollectAndCount(serverPlayersOnline)
+	_, err := p.Register(info)
+	require.NoError(t, err)
+	require.Equal(t, before+1, testutil.CollectAndCount(serverPlayersOnline))
+
+	require.True(t, p.Unregister(info))
+	require.Equal(t, before, testutil.CollectAndCount(serverPlayersOnline))
+}
@@ -0,0 +1,253 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// PacketInterceptor intercepts a packet in the play state before the proxy handles it.
+// It may inspect, modify or cancel the packet and inject other packets.
+//
+// Interceptors are called in the read loop of the connection the packet was received from
+// and must therefore return quickly and not block.
+type PacketInterceptor func(c *PacketInterceptContext)
+
+// PacketInterceptContext is the context of a packet intercepted by a PacketInterceptor.
+//
+// The Direction of the embedded proto.PacketContext tells whether the packet was
+// received from the player (proto.ServerBound) or the backend server (proto.ClientBound).
+type PacketInterceptContext struct {
+	*proto.PacketContext
+
+	player     *connectedPlayer
+	serverConn *serverConnection // nil-able
+	cancelled  bool
+	modified   bool
+}
+
+// Player returns the player whose connection the packet is flowing through.
+func (c *PacketInterceptContext) Player() Player {
+	return c.player
+}
+
+// Server returns the player's connection to the backend server
+// the packet was received from or is forwarded to.
+// Returns nil if the player is not connected to a server.
+func (c *PacketInterceptContext) Server() ServerConnection {
+	if c.serverConn == nil {
+		return nil // return correct nil
+	}
+	return c.serverConn
+}
+
+// Cancel cancels the packet so that neither the proxy handles it
+// nor it is forwarded. Following interceptors are still called.
+func (c *PacketInterceptContext) Cancel() {
+	c.cancelled = true
+}
+
+// Cancelled returns true if the packet was cancelled.
+func (c *PacketInterceptContext) Cancelled() bool {
+	return c.cancelled
+}
+
+// SetPacket replaces the intercepted packet. The packet is encoded in the place of the
+// received payload, so it must be registered in the connection's current state.
+// Modifying the fields of the packet returned by Packet requires to call SetPacket as well.
+func (c *PacketInterceptContext) SetPacket(packet proto.Packet) {
+	c.Packet = packet
+	c.modified = true
+}
+
+// WritePlayer writes a packet to the player.
+func (c *PacketInterceptContext) WritePlayer(packet proto.Packet) error {
+	return c.player.WritePacket(packet)
+}
+
+// WriteServer writes a packet to the backend server the player is connected to.
+func (c *PacketInterceptContext) WriteServer(packet proto.Packet) error {
+	serverMc, ok := c.serverConn.ensureConnected()
+	if !ok {
+		return errors.New("player is not connected to a server")
+	}
+	return serverMc.WritePacket(packet)
+}
+
+// InterceptPacket registers a PacketInterceptor for packets of the type of packetOf bound
+// to the direction. If packetOf is nil, all packets bound to the direction are intercepted,
+// including packets unknown to the proxy. Interceptors are called in the order they were
+// registered, regardless of whether they intercept all packets or a packet type.
+//
+// Intercepted are packets received from players (proto.ServerBound) or backend servers
+// (proto.ClientBound) in the play state. Packets sent by the proxy are not intercepted.
+func (p *Proxy) InterceptPacket(
+	direction proto.Direction,
+	packetOf proto.Packet,
+	interceptor PacketInterceptor,
+) (unregister func()) {
+	return p.packetInterceptors.add(direction, packetOf, interceptor)
+}
+
+type packetInterceptorKey struct {
+	direction  proto.Direction
+	packetType reflect.Type // nil for all packets
+}
+
+type packetInterceptorEntry struct {
+	seq         uint64 // registration order
+	interceptor PacketInterceptor
+}
+
+// packetInterceptors is the chain of registered packet interceptors.
+type packetInterceptors struct {
+	mu           sync.RWMutex // Protects following fields
+	seq          uint64
+	interceptors map[packetInterceptorKey][]*packetInterceptorEntry
+}
+
+func (i *packetInterceptors) add(
+	direction proto.Direction,
+	packetOf proto.Packet,
+	interceptor PacketInterceptor,
+) (unregister func()) {
+	key := packetInterceptorKey{direction: direction}
+	if packetOf != nil {
+		key.packetType = proto.TypeOf(packetOf)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.seq++
+	entry := &packetInterceptorEntry{seq: i.seq, interceptor: interceptor}
+	if i.interceptors == nil {
+		i.interceptors = map[packetInterceptorKey][]*packetInterceptorEntry{}
+	}
+	i.interceptors[key] = append(i.interceptors[key], entry)
+
+	return func() {
+		i.mu.Lock()
+		defer i.mu.Unlock()
+		list := i.interceptors[key]
+		for idx, e := range list {
+			if e == entry {
+				// Copy to not modify the list a running chain loops over
+				list = append(list[:idx:idx], list[idx+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(i.interceptors, key)
+		} else {
+			i.interceptors[key] = list
+		}
+	}
+}
+
+// chain returns the interceptors for the packet in registration order.
+func (i *packetInterceptors) chain(pc *proto.PacketContext) []*packetInterceptorEntry {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if len(i.interceptors) == 0 {
+		return nil
+	}
+	all := i.interceptors[packetInterceptorKey{direction: pc.Direction}]
+	if !pc.KnownPacket {
+		return all
+	}
+	typed := i.interceptors[packetInterceptorKey{
+		direction:  pc.Direction,
+		packetType: proto.TypeOf(pc.Packet),
+	}]
+	if len(all) == 0 {
+		return typed
+	}
+	if len(typed) == 0 {
+		return all
+	}
+	// Merge both lists, each is already ordered
+	chain := make([]*packetInterceptorEntry, 0, len(all)+len(typed))
+	for len(all) != 0 && len(typed) != 0 {
+		if all[0].seq < typed[0].seq {
+			chain, all = append(chain, all[0]), all[1:]
+		} else {
+			chain, typed = append(chain, typed[0]), typed[1:]
+		}
+	}
+	chain = append(chain, all...)
+	return append(chain, typed...)
+}
+
+// interceptPacket runs the packet interceptors for a packet received by conn and returns
+// false if the packet was cancelled. A modified packet is encoded into the payload.
+func (p *Proxy) interceptPacket(
+	conn netmc.MinecraftConn,
+	pc *proto.PacketContext,
+	player *connectedPlayer,
+	serverConn *serverConnection,
+) bool {
+	chain := p.packetInterceptors.chain(pc)
+	if len(chain) == 0 {
+		return true
+	}
+
+	c := &PacketInterceptContext{
+		PacketContext: pc,
+		player:        player,
+		serverConn:    serverConn,
+	}
+	for _, entry := range chain {
+		p.runPacketInterceptor(entry.interceptor, c)
+	}
+	if c.cancelled {
+		return false
+	}
+	if c.modified {
+		if err := encodePayload(conn, pc); err != nil {
+			p.log.Error(err, "error encoding packet modified by interceptor, dropping it",
+				"packet", fmt.Sprintf("%T", pc.Packet))
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Proxy) runPacketInterceptor(interceptor PacketInterceptor, c *PacketInterceptContext) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Error(nil, "recovered panic in packet interceptor", "panic", r)
+		}
+	}()
+	interceptor(c)
+}
+
+// encodePayload encodes the packet of pc into its payload
+// using the packet registry of the conn's current state.
+func encodePayload(conn netmc.MinecraftConn, pc *proto.PacketContext) error {
+	if pc.Packet == nil {
+		return errors.New("packet must not be nil")
+	}
+	registry := conn.State().ClientBound
+	if pc.Direction == proto.ServerBound {
+		registry = conn.State().ServerBound
+	}
+	id, found := registry.ProtocolRegistry(pc.Protocol).PacketID(pc.Packet)
+	if !found {
+		return fmt.Errorf("packet %T is not registered in the %s state", pc.Packet, conn.State())
+	}
+	pc.PacketID = id
+	pc.KnownPacket = true
+	buf := new(bytes.Buffer)
+	_ = util.WriteVarInt(buf, int(id))
+	if err := pc.Packet.Encode(pc, buf); err != nil {
+		return err
+	}
+	pc.Payload = buf.Bytes()
+	return nil
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/state"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// playConn is a netmc.MinecraftConn in the play state.
+type playConn struct{ netmc.MinecraftConn }
+
+func (playConn) State() *state.Registry { return state.Play }
+
+func keepAliveContext() *proto.PacketContext {
+	return &proto.PacketContext{
+		Direction:   proto.ClientBound,
+		Protocol:    version.MaximumVersion.Protocol,
+		KnownPacket: true,
+		Packet:      &packet.KeepAlive{RandomID: 1},
+		Payload:     []byte{0},
+	}
+}
+
+func TestPacketInterceptors_chain(t *testing.T) {
+	var calls []string
+	intercept := func(name string) PacketInterceptor {
+		return func(*PacketInterceptContext) { calls = append(calls, name) }
+	}
+	run := func(i *packetInterceptors, pc *proto.PacketContext) []string {
+		calls = nil
+		for _, entry := range i.chain(pc) {
+			entry.interceptor(nil)
+		}
+		return calls
+	}
+
+	i := new(packetInterceptors)
+	require.Empty(t, run(i, keepAliveContext()))
+
+	i.add(proto.ClientBound, &packet.KeepAlive{}, intercept("typed1"))
+	i.add(proto.ClientBound, nil, intercept("all1"))
+	unregister := i.add(proto.ClientBound, &packet.KeepAlive{}, intercept("typed2"))
+	i.add(proto.ClientBound, nil, intercept("all2"))
+	i.add(proto.ServerBound, nil, intercept("serverBound"))
+	i.add(proto.ClientBound, &packet.Disconnect{}, intercept("otherType"))
+
+	// Called in registration order
+	require.Equal(t, []string{"typed1", "all1", "typed2", "all2"}, run(i, keepAliveContext()))
+
+	// Unknown packets are only intercepted by interceptors of all packets
+	unknown := &proto.PacketContext{Direction: proto.ClientBound, PacketID: 0x7f}
+	require.Equal(t, []string{"all1", "all2"}, run(i, unknown))
+
+	unregister()
+	unregister() // no-op
+	require.Equal(t, []string{"typed1", "all1", "all2"}, run(i, keepAliveContext()))
+}
+
+func TestProxy_interceptPacket(t *testing.T) {
+	t.Run("cancel", func(t *testing.T) {
+		p := newTestProxy(t, config.DefaultConfig)
+		var cancelledSeen bool
+		p.InterceptPacket(proto.ClientBound, nil, func(c *PacketInterceptContext) {
+			c.Cancel()
+		})
+		p.InterceptPacket(proto.ClientBound, &packet.KeepAlive{}, func(c *PacketInterceptContext) {
+			cancelledSeen = c.Cancelled()
+		})
+		require.False(t, p.interceptPacket(playConn{}, keepAliveContext(), nil, nil))
+		require.True(t, cancelledSeen, "following interceptors must still be called")
+	})
+	t.Run("set packet", func(t *testing.T) {
+		p := newTestProxy(t, config.DefaultConfig)
+		p.InterceptPacket(proto.ClientBound, &packet.KeepAlive{}, func(c *PacketInterceptContext) {
+			c.SetPacket(&packet.KeepAlive{RandomID: 42})
+		})
+		pc := keepAliveContext()
+		require.True(t, p.interceptPacket(playConn{}, pc, nil, nil))
+
+		rd := bytes.NewReader(pc.Payload)
+		id, err := util.ReadVarInt(rd)
+		require.NoError(t, err)
+		require.EqualValues(t, pc.PacketID, id)
+		wantID, ok := state.Play.ClientBound.ProtocolRegistry(pc.Protocol).PacketID(&packet.KeepAlive{})
+		require.True(t, ok)
+		require.Equal(t, wantID, pc.PacketID)
+
+		decoded := new(packet.KeepAlive)
+		require.NoError(t, decoded.Decode(pc, rd))
+		require.EqualValues(t, 42, decoded.RandomID)
+	})
+	t.Run("unregistered packet is dropped", func(t *testing.T) {
+		p := newTestProxy(t, config.DefaultConfig)
+		p.InterceptPacket(proto.ClientBound, &packet.KeepAlive{}, func(c *PacketInterceptContext) {
+			c.SetPacket(&packet.Handshake{})
+		})
+		require.False(t, p.interceptPacket(playConn{}, keepAliveContext(), nil, nil))
+	})
+	t.Run("unregister", func(t *testing.T) {
+		p := newTestProxy(t, config.DefaultConfig)
+		unregister := p.InterceptPacket(proto.ClientBound, nil, func(c *PacketInterceptContext) {
+			c.Cancel()
+		})
+		require.False(t, p.interceptPacket(playConn{}, keepAliveContext(), nil, nil))
+		unregister()
+		require.True(t, p.interceptPacket(playConn{}, keepAliveContext(), nil, nil))
+	})
+	t.Run("recover panic", func(t *testing.T) {
+		p := newTestProxy(t, config.DefaultConfig)
+		var called bool
+		p.InterceptPacket(proto.ClientBound, nil, func(*PacketInterceptContext) {
+			panic("test")
+		})
+		p.InterceptPacket(proto.ClientBound, nil, func(*PacketInterceptContext) {
+			called = true
+		})
+		pc := keepAliveContext()
+		require.True(t, p.interceptPacket(playConn{}, pc, nil, nil))
+		require.True(t, called)
+		require.Equal(t, []byte{0}, pc.Payload, "unmodified packet must keep its payload")
+	})
+}
@@ -0,0 +1,243 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/groupcache/singleflight"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/ping"
+	"go.minekube.com/gate/pkg/edition/java/proto/codec"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/state"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/internal/proxyproto"
+	"go.minekube.com/gate/pkg/util/netutil"
+)
+
+// serverPinger pings backend servers for the server list ping
+// passthrough and caches their responses.
+type serverPinger struct {
+	group singleflight.Group // deduplicates concurrent pings of the same server
+
+	mu    sync.Mutex // Protects following field
+	cache map[pingCacheKey]*cachedPing
+}
+
+type pingCacheKey struct {
+	server   string // lowercase server name
+	protocol proto.Protocol
+}
+
+type cachedPing struct {
+	ping    *ping.ServerPing // nil if err is set
+	err     error
+	expires time.Time
+}
+
+// passthroughPing merges the ping responses of the backend servers a client connecting to
+// the inbound's virtual host would be sent to into the initial ping, depending on the
+// configured ping passthrough mode. The first server in order that responded within the
+// timeout is used. The initial ping is returned if no backend server responded.
+func (p *Proxy) passthroughPing(inbound Inbound, protocol proto.Protocol, initial *ping.ServerPing) *ping.ServerPing {
+	cfg := p.config().Status.PingPassthrough
+	if cfg.Mode == config.DisabledPingPassthroughMode {
+		return initial
+	}
+
+	var servers []*registeredServer
+	for _, name := range serverNamesToTry(p.config(), inbound.VirtualHost()) {
+		if s := p.server(name); s != nil {
+			servers = append(servers, s)
+		}
+	}
+	if len(servers) == 0 {
+		return initial
+	}
+
+	if !version.Protocol(protocol).Supported() {
+		protocol = version.MaximumVersion.Protocol
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Millisecond)
+	defer cancel()
+
+	// Ping all servers at once and use the first in order that responded
+	pongs := make([]*ping.ServerPing, len(servers))
+	var wg sync.WaitGroup
+	wg.Add(len(servers))
+	for i, server := range servers {
+		go func(i int, server *registeredServer) {
+			defer wg.Done()
+			pong, err := p.pinger.ping(ctx, p, server, protocol, inbound.RemoteAddr())
+			if err != nil {
+				p.log.V(1).Info("could not ping backend server for ping passthrough",
+					"server", server.ServerInfo().Name(), "error", err)
+				return
+			}
+			pongs[i] = pong
+		}(i, server)
+	}
+	wg.Wait()
+
+	for _, pong := range pongs {
+		if pong != nil {
+			return mergePing(cfg.Mode, initial, pong)
+		}
+	}
+	return initial
+}
+
+// mergePing merges the backend server ping into the initial ping.
+// The backend server ping is not modified since it may be cached.
+func mergePing(mode config.PingPassthroughMode, initial, backend *ping.ServerPing) *ping.ServerPing {
+	merged := *initial
+	if backend.Description != nil {
+		merged.Description = backend.Description
+	}
+	if backend.Favicon != "" {
+		merged.Favicon = backend.Favicon
+	}
+	if mode != config.AllPingPassthroughMode {
+		return &merged
+	}
+	merged.Version = backend.Version
+	if backend.Players != nil {
+		players := *backend.Players
+		players.Sample = append([]ping.SamplePlayer(nil), backend.Players.Sample...)
+		merged.Players = &players
+	}
+	return &merged
+}
+
+// ping returns the cached ping response of the server or pings the server.
+func (s *serverPinger) ping(
+	ctx context.Context,
+	p *Proxy,
+	server *registeredServer,
+	protocol proto.Protocol,
+	source net.Addr,
+) (*ping.ServerPing, error) {
+	key := pingCacheKey{server: strings.ToLower(server.ServerInfo().Name()), protocol: protocol}
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.ping, cached.err
+	}
+
+	v, err := s.group.Do(fmt.Sprintf("%s/%d", key.server, key.protocol), func() (any, error) {
+		pong, err := pingServer(ctx, server, protocol, source,
+			p.config().ProxyProtocolFor(server.ServerInfo().Name()), p.log)
+
+		ttl := time.Duration(p.config().Status.PingPassthrough.CacheTTL) * time.Millisecond
+		if ttl > 0 {
+			now := time.Now()
+			s.mu.Lock()
+			if s.cache == nil {
+				s.cache = map[pingCacheKey]*cachedPing{}
+			}
+			// Evict expired responses, e.g. of servers or protocols not pinged anymore
+			for k, c := range s.cache {
+				if !now.Before(c.expires) {
+					delete(s.cache, k)
+				}
+			}
+			s.cache[key] = &cachedPing{ping: pong, err: err, expires: now.Add(ttl)}
+			s.mu.Unlock()
+		}
+		return pong, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ping.ServerPing), nil
+}
+
+// forget removes the cached ping responses of the server with the given name.
+func (s *serverPinger) forget(server string) {
+	server = strings.ToLower(server)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range s.cache {
+		if k.server == server {
+			delete(s.cache, k)
+		}
+	}
+}
+
+// pingServer sends a server list ping to the backend server and returns its response.
+func pingServer(
+	ctx context.Context,
+	server RegisteredServer,
+	protocol proto.Protocol,
+	source net.Addr,
+	sendProxyHeader bool,
+	log logr.Logger,
+) (*ping.ServerPing, error) {
+	addr := server.ServerInfo().Addr()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if sendProxyHeader {
+		b, err := proxyproto.NewHeader(source, conn.RemoteAddr()).Format()
+		if err != nil {
+			return nil, fmt.Errorf("error creating PROXY protocol header: %w", err)
+		}
+		if _, err = conn.Write(b); err != nil {
+			return nil, fmt.Errorf("error writing PROXY protocol header: %w", err)
+		}
+	}
+
+	enc := codec.NewEncoder(conn, proto.ServerBound, log)
+	dec := codec.NewDecoder(conn, proto.ClientBound, log)
+	enc.SetProtocol(protocol)
+	dec.SetProtocol(protocol)
+
+	host, port := netutil.HostPort(addr)
+	if _, err = enc.WritePacket(&packet.Handshake{
+		ProtocolVersion: int(protocol),
+		ServerAddress:   host,
+		Port:            int(port),
+		NextStatus:      int(state.StatusState),
+	}); err != nil {
+		return nil, err
+	}
+	enc.SetState(state.Status)
+	dec.SetState(state.Status)
+	if _, err = enc.WritePacket(&packet.StatusRequest{}); err != nil {
+		return nil, err
+	}
+
+	pc, err := dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := pc.Packet.(*packet.StatusResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected packet in response to status request: %s", pc)
+	}
+	pong := new(ping.ServerPing)
+	if err = json.Unmarshal([]byte(res.Status), pong); err != nil {
+		return nil, fmt.Errorf("error unmarshal status response: %w", err)
+	}
+	return pong, nil
+}
@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+func TestServerPinger_cacheEviction(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.Status.PingPassthrough.CacheTTL = 60000
+	p := newTestProxy(t, cfg)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go serveStatus(ln, `{"version":{"name":"test","protocol":763},"description":{"text":"hi"}}`)
+
+	rs, err := p.Register(NewServerInfo("Lobby", ln.Addr()))
+	require.NoError(t, err)
+	s := rs.(*registeredServer)
+	source := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 50000}
+	ping := func(protocol proto.Protocol) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		pong, err := p.pinger.ping(ctx, p, s, protocol, source)
+		require.NoError(t, err)
+		require.Equal(t, "test", pong.Version.Name)
+	}
+	cached := func() []pingCacheKey {
+		p.pinger.mu.Lock()
+		defer p.pinger.mu.Unlock()
+		var keys []pingCacheKey
+		for k := range p.pinger.cache {
+			keys = append(keys, k)
+		}
+		return keys
+	}
+
+	ping(version.MaximumVersion.Protocol)
+	require.Equal(t, []pingCacheKey{{server: "lobby", protocol: version.MaximumVersion.Protocol}}, cached())
+
+	// Expired responses are evicted when another response is cached
+	p.pinger.mu.Lock()
+	for _, c := range p.pinger.cache {
+		c.expires = time.Now()
+	}
+	p.pinger.mu.Unlock()
+	ping(version.Minecraft_1_16_4.Protocol)
+	require.Equal(t, []pingCacheKey{{server: "lobby", protocol: version.Minecraft_1_16_4.Protocol}}, cached())
+
+	// Unregistering the server evicts its responses
+	require.True(t, p.Unregister(s.ServerInfo()))
+	require.Empty(t, cached())
+}
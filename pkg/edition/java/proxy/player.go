@@ -27,7 +27,6 @@ import (
 	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/title"
-	"go.minekube.com/gate/pkg/edition/java/proto/util"
 	"go.minekube.com/gate/pkg/edition/java/proto/version"
 	"go.minekube.com/gate/pkg/edition/java/proxy/message"
 	"go.minekube.com/gate/pkg/edition/java/proxy/player"
@@ -55,6 +54,7 @@ type Player interface {
 	CreateConnectionRequest(target RegisteredServer) ConnectionRequest
 	GameProfile() profile.GameProfile // Returns the player's game profile.
 	Settings() player.Settings        // The player's client settings. Returns player.DefaultSettings if unknown.
+	ClientBrand() string              // The brand of the player's client, e.g. "vanilla". Empty if unknown.
 	// Disconnect disconnects the player with a reason.
 	// Once called, further interface calls to this player become undefined.
 	Disconnect(reason component.Component)
@@ -67,8 +67,16 @@ type Player interface {
 	SendResourcePack(info ResourcePackInfo) error
 	// SendActionBar sends an action bar to the player.
 	SendActionBar(msg component.Component) error
+	// ShowTitle shows a title and optional subtitle (may be nil) to the player.
+	// The times are rounded down to ticks (50ms). If they are all zero,
+	// the times of the last title or the client's defaults are used.
+	ShowTitle(title, subtitle component.Component, fadeIn, stay, fadeOut time.Duration) error
+	// ClearTitle hides the title currently shown to the player.
+	ClearTitle() error
+	// ResetTitle hides the title currently shown to the player and
+	// resets the title, subtitle and times to the client's defaults.
+	ResetTitle() error
 	TabList() tablist.TabList // Returns the player's tab list.
-	// TODO add title and more
 }
 
 type connectedPlayer struct {
@@ -87,6 +95,9 @@ type connectedPlayer struct {
 	// due to another connection logging in with the same GameProfile.
 	disconnectDueToDuplicateConnection atomic.Bool
 
+	// This field is true while the tab list header and footer is being refreshed.
+	refreshingHeaderFooter atomic.Bool
+
 	pluginChannelsMu sync.RWMutex // Protects following field
 	pluginChannels   sets.String  // Known plugin channels
 
@@ -96,7 +107,9 @@ type connectedPlayer struct {
 	connectedServer_         *serverConnection
 	connInFlight             *serverConnection
 	settings                 player.Settings
+	clientSettingsPacket     *packet.ClientSettings
 	modInfo                  *modinfo.ModInfo
+	clientBrand              string
 	connPhase                phase.ClientConnectionPhase
 	outstandingResourcePacks deque.Deque[*ResourcePackInfo]
 	previousResourceResponse *bool
@@ -380,6 +393,8 @@ func (p *connectedPlayer) Active() bool {
 }
 
 // WithMessageSender modifies the sender identity of the chat message.
+// Chat messages of a sender that is a player on the proxy are shown to
+// 1.19.3+ clients like player chat messages, without being signed.
 func WithMessageSender(id uuid.UUID) command.MessageOption {
 	return messageApplyOption(func(o any) {
 		if b, ok := o.(*packet.ChatBuilder); ok {
@@ -411,7 +426,7 @@ const (
 func WithMessageType(t MessageType) command.MessageOption {
 	return messageApplyOption(func(o any) {
 		if b, ok := o.(*packet.ChatBuilder); ok {
-			if t != ChatMessageType {
+			if t > GameInfoMessageType {
 				t = SystemMessageType
 			}
 			b.Type(t)
@@ -427,17 +442,17 @@ func (p *connectedPlayer) SendMessage(msg component.Component, opts ...command.M
 	if msg == nil {
 		return nil // skip nil message
 	}
-	m := new(strings.Builder)
-	if err := util.JsonCodec(p.Protocol()).Marshal(m, msg); err != nil {
-		return err
-	}
 	chat := packet.NewChatBuilder(p.Protocol()).
 		Component(msg).
-		AsPlayer(p.ID()).
 		Type(ChatMessageType)
 	for _, o := range opts {
 		o.Apply(chat)
 	}
+	if id, ok := chat.Sender(); ok {
+		if sender := p.proxy.Player(id); sender != nil {
+			chat.SenderName(&component.Text{Content: sender.Username()})
+		}
+	}
 	return p.WritePacket(chat.ToClient())
 }
 
@@ -448,6 +463,13 @@ func (p *connectedPlayer) SendActionBar(msg component.Component) error {
 		return nil // skip nil message
 	}
 	protocol := p.Protocol()
+	if protocol.GreaterEqual(version.Minecraft_1_19) {
+		// Use the system chat packet shown as overlay.
+		return p.WritePacket(&packet.SystemChat{
+			Component: msg,
+			Type:      packet.GameInfoMessageType,
+		})
+	}
 	if protocol.GreaterEqual(version.Minecraft_1_11) {
 		// Use the title packet instead.
 		pkt, err := title.New(protocol, &title.Builder{
@@ -470,13 +492,68 @@ func (p *connectedPlayer) SendActionBar(msg component.Component) error {
 	if err != nil {
 		return err
 	}
-	return p.WritePacket(&packet.LegacyChat{ // TODO 1.19 chat
+	return p.WritePacket(&packet.LegacyChat{
 		Message: string(m),
 		Type:    packet.GameInfoMessageType,
 		Sender:  uuid.Nil,
 	})
 }
 
+// tickDuration is the duration of a game tick.
+const tickDuration = time.Second / 20
+
+func (p *connectedPlayer) ShowTitle(text, subtitle component.Component, fadeIn, stay, fadeOut time.Duration) error {
+	if text == nil {
+		return errors.New("title must not be nil")
+	}
+	builders := make([]*title.Builder, 0, 3)
+	if fadeIn != 0 || stay != 0 || fadeOut != 0 {
+		builders = append(builders, &title.Builder{
+			Action:  title.SetTimes,
+			FadeIn:  int(fadeIn / tickDuration),
+			Stay:    int(stay / tickDuration),
+			FadeOut: int(fadeOut / tickDuration),
+		})
+	}
+	if subtitle != nil {
+		builders = append(builders, &title.Builder{Action: title.SetSubtitle, Component: subtitle})
+	}
+	// The title must be sent last as it makes the client show the title.
+	builders = append(builders, &title.Builder{Action: title.SetTitle, Component: text})
+	return p.writeTitle(builders...)
+}
+
+func (p *connectedPlayer) ClearTitle() error {
+	return p.writeTitle(&title.Builder{Action: title.Hide})
+}
+
+func (p *connectedPlayer) ResetTitle() error {
+	return p.writeTitle(&title.Builder{Action: title.Reset})
+}
+
+// writeTitle writes the title packets for the player's protocol version.
+func (p *connectedPlayer) writeTitle(builders ...*title.Builder) error {
+	protocol := p.Protocol()
+	for _, b := range builders {
+		pkt, err := title.New(protocol, b)
+		if err != nil {
+			return err
+		}
+		if err = p.BufferPacket(pkt); err != nil {
+			return err
+		}
+	}
+	return p.Flush()
+}
+
+// BroadcastTitle shows a title to all given players, e.g. for network-wide announcements.
+// See Player.ShowTitle for details.
+func BroadcastTitle(players []Player, text, subtitle component.Component, fadeIn, stay, fadeOut time.Duration) {
+	for _, player := range players {
+		go func(p Player) { _ = p.ShowTitle(text, subtitle, fadeIn, stay, fadeOut) }(player)
+	}
+}
+
 func (p *connectedPlayer) SendPluginMessage(identifier message.ChannelIdentifier, data []byte) error {
 	return p.WritePacket(&plugin.Message{
 		Channel: identifier.ID(),
@@ -489,19 +566,17 @@ func (p *connectedPlayer) SendPluginMessage(identifier message.ChannelIdentifier
 // Finds another server to attempt to log into, if we were unexpectedly disconnected from the server.
 // current is the current server of the player is on, so we skip this server and not connect to it.
 // current can be nil if there is no current server.
+// Servers found offline by the last health check are only tried if all others are offline too.
 // MAY RETURN NIL if no next server available!
 func (p *connectedPlayer) nextServerToTry(current RegisteredServer) RegisteredServer {
+	healthCheck := p.config().HealthCheck.Enabled // skip offline servers
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if len(p.serversToTry) == 0 {
-		p.serversToTry = p.config().ForcedHosts[p.virtualHost.String()]
-	}
-	if len(p.serversToTry) == 0 {
-		connOrder := p.config().Try
-		if len(connOrder) == 0 {
+		p.serversToTry = serverNamesToTry(p.config(), p.virtualHost)
+		if len(p.serversToTry) == 0 {
 			return nil
-		} else {
-			p.serversToTry = connOrder
 		}
 	}
 
@@ -509,6 +584,8 @@ func (p *connectedPlayer) nextServerToTry(current RegisteredServer) RegisteredSe
 		return rs.ServerInfo().Name() == name
 	}
 
+	offlineIndex := -1 // the first server found offline
+	var offline *registeredServer
 	for i := p.tryIndex; i < len(p.serversToTry); i++ {
 		toTry := p.serversToTry[i]
 		if (p.connectedServer_ != nil && sameName(p.connectedServer_.Server(), toTry)) ||
@@ -517,14 +594,38 @@ func (p *connectedPlayer) nextServerToTry(current RegisteredServer) RegisteredSe
 			continue
 		}
 
-		p.tryIndex = i
-		if s := p.proxy.Server(toTry); s != nil {
-			return s
+		s := p.proxy.server(toTry)
+		if s == nil {
+			continue
+		}
+		if healthCheck && s.Status().offline() {
+			if offline == nil {
+				offlineIndex, offline = i, s
+			}
+			continue
 		}
+		p.tryIndex = i
+		return s
+	}
+	if offline != nil {
+		// The health check may be outdated, so better try an offline server than none.
+		p.tryIndex = offlineIndex
+		return offline
 	}
 	return nil
 }
 
+// serverNamesToTry returns the names of the servers configured for
+// the forced host the client connected with or else the try servers.
+func serverNamesToTry(cfg *config.Config, virtualHost net.Addr) []string {
+	if virtualHost != nil {
+		if names := cfg.ForcedHosts.Servers(virtualHost.String()); len(names) != 0 {
+			return names
+		}
+	}
+	return cfg.Try
+}
+
 // player's connection is closed at this point,
 // now need to disconnect backend server connection, if any.
 func (p *connectedPlayer) teardown() {
@@ -626,6 +727,31 @@ func (p *connectedPlayer) SetModInfo(info *modinfo.ModInfo) {
 	}
 }
 
+func (p *connectedPlayer) ClientBrand() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.clientBrand
+}
+
+// handleClientBrand reads the brand of a brand plugin message sent by the client
+// and fires a PlayerClientBrandEvent when the client sent its brand the first time.
+func (p *connectedPlayer) handleClientBrand(msg *plugin.Message) {
+	brand := plugin.ReadBrandMessage(msg.Data)
+	p.mu.Lock()
+	first := p.clientBrand == ""
+	if first {
+		p.clientBrand = brand
+	}
+	p.mu.Unlock()
+
+	if first && brand != "" {
+		p.eventMgr.FireParallel(&PlayerClientBrandEvent{
+			player: p,
+			brand:  brand,
+		})
+	}
+}
+
 // NOTE: the returned set is not goroutine-safe and must not be modified,
 // it is only for reading!!!
 func (p *connectedPlayer) knownChannels() sets.String {
@@ -655,6 +781,7 @@ func (p *connectedPlayer) setSettings(settings *packet.ClientSettings) {
 	wrapped := player.NewSettings(settings)
 	p.mu.Lock()
 	p.settings = wrapped
+	p.clientSettingsPacket = settings
 	p.mu.Unlock()
 
 	p.eventMgr.Fire(&PlayerSettingsChangedEvent{
@@ -663,6 +790,14 @@ func (p *connectedPlayer) setSettings(settings *packet.ClientSettings) {
 	})
 }
 
+// clientSettings returns the last client settings packet
+// sent by the player or nil if not received yet.
+func (p *connectedPlayer) clientSettings() *packet.ClientSettings {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.clientSettingsPacket
+}
+
 // Settings returns the players client settings.
 // If not known already, returns player.DefaultSettings.
 func (p *connectedPlayer) Settings() player.Settings {
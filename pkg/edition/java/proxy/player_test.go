@@ -0,0 +1,215 @@
+package proxy
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/title"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/gate/proto"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+// recordingConn is a netmc.MinecraftConn that records the packets written to it.
+type recordingConn struct {
+	netmc.MinecraftConn
+	protocol proto.Protocol
+
+	mu       sync.Mutex
+	packets  []proto.Packet
+	flushed  []proto.Packet // packets written until the last flush
+	payloads [][]byte       // forwarded packet payloads
+}
+
+func (c *recordingConn) Protocol() proto.Protocol { return c.protocol }
+
+func (c *recordingConn) BufferPacket(p proto.Packet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.packets = append(c.packets, p)
+	return nil
+}
+
+func (c *recordingConn) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushed = c.packets
+	return nil
+}
+
+func (c *recordingConn) WritePacket(p proto.Packet) error {
+	if err := c.BufferPacket(p); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+func (c *recordingConn) Write(payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.payloads = append(c.payloads, payload)
+	return nil
+}
+
+func (c *recordingConn) forwarded() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.payloads
+}
+
+func (c *recordingConn) written() []proto.Packet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.flushed
+}
+
+func newRecordingPlayer(protocol proto.Protocol) (*connectedPlayer, *recordingConn) {
+	conn := &recordingConn{protocol: protocol}
+	return &connectedPlayer{MinecraftConn: conn}, conn
+}
+
+func jsonComponent(t *testing.T, protocol proto.Protocol, c component.Component) string {
+	b := new(strings.Builder)
+	require.NoError(t, util.JsonCodec(protocol).Marshal(b, c))
+	return b.String()
+}
+
+func TestConnectedPlayer_ShowTitle(t *testing.T) {
+	text := &component.Text{Content: "Welcome"}
+	subtitle := &component.Text{Content: "to Gate"}
+
+	t.Run("legacy", func(t *testing.T) {
+		protocol := version.Minecraft_1_16_4.Protocol
+		p, conn := newRecordingPlayer(protocol)
+		require.NoError(t, p.ShowTitle(text, subtitle, time.Second, 3*time.Second, 500*time.Millisecond))
+		require.Equal(t, []proto.Packet{
+			&title.Legacy{Action: title.SetTimes, FadeIn: 20, Stay: 60, FadeOut: 10},
+			&title.Legacy{Action: title.SetSubtitle, Component: jsonComponent(t, protocol, subtitle)},
+			&title.Legacy{Action: title.SetTitle, Component: jsonComponent(t, protocol, text)},
+		}, conn.written())
+	})
+	t.Run("1.17+", func(t *testing.T) {
+		protocol := version.Minecraft_1_17.Protocol
+		p, conn := newRecordingPlayer(protocol)
+		require.NoError(t, p.ShowTitle(text, subtitle, time.Second, 3*time.Second, 500*time.Millisecond))
+		require.Equal(t, []proto.Packet{
+			&title.Times{FadeIn: 20, Stay: 60, FadeOut: 10},
+			&title.Subtitle{Component: jsonComponent(t, protocol, subtitle)},
+			&title.Text{Component: jsonComponent(t, protocol, text)},
+		}, conn.written())
+	})
+	t.Run("without times and subtitle", func(t *testing.T) {
+		protocol := version.Minecraft_1_17.Protocol
+		p, conn := newRecordingPlayer(protocol)
+		require.NoError(t, p.ShowTitle(text, nil, 0, 0, 0))
+		require.Equal(t, []proto.Packet{
+			&title.Text{Component: jsonComponent(t, protocol, text)},
+		}, conn.written())
+	})
+	t.Run("nil title", func(t *testing.T) {
+		p, conn := newRecordingPlayer(version.Minecraft_1_17.Protocol)
+		require.Error(t, p.ShowTitle(nil, subtitle, 0, 0, 0))
+		require.Empty(t, conn.written())
+	})
+}
+
+func TestConnectedPlayer_ClearResetTitle(t *testing.T) {
+	tests := []struct {
+		name         string
+		protocol     proto.Protocol
+		clear, reset proto.Packet
+	}{
+		{
+			name:     "legacy",
+			protocol: version.Minecraft_1_16_4.Protocol,
+			clear:    &title.Legacy{Action: title.Hide},
+			reset:    &title.Legacy{Action: title.Reset},
+		},
+		{
+			name:     "1.17+",
+			protocol: version.Minecraft_1_17.Protocol,
+			clear:    &title.Clear{Action: title.Hide},
+			reset:    &title.Clear{Action: title.Reset},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, conn := newRecordingPlayer(tt.protocol)
+			require.NoError(t, p.ClearTitle())
+			require.Equal(t, []proto.Packet{tt.clear}, conn.written())
+
+			p, conn = newRecordingPlayer(tt.protocol)
+			require.NoError(t, p.ResetTitle())
+			require.Equal(t, []proto.Packet{tt.reset}, conn.written())
+		})
+	}
+}
+
+func TestBroadcastTitle(t *testing.T) {
+	text := &component.Text{Content: "Restarting"}
+	legacy, legacyConn := newRecordingPlayer(version.Minecraft_1_16_4.Protocol)
+	modern, modernConn := newRecordingPlayer(version.Minecraft_1_17.Protocol)
+
+	BroadcastTitle([]Player{legacy, modern}, text, nil, 0, time.Second, 0)
+
+	require.Eventually(t, func() bool {
+		return len(legacyConn.written()) == 2 && len(modernConn.written()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []proto.Packet{
+		&title.Legacy{Action: title.SetTimes, Stay: 20},
+		&title.Legacy{Action: title.SetTitle, Component: jsonComponent(t, legacy.Protocol(), text)},
+	}, legacyConn.written())
+	require.Equal(t, []proto.Packet{
+		&title.Times{Stay: 20},
+		&title.Text{Component: jsonComponent(t, modern.Protocol(), text)},
+	}, modernConn.written())
+}
+
+func brandMessage(t *testing.T, channel, brand string) *plugin.Message {
+	buf := new(bytes.Buffer)
+	require.NoError(t, util.WriteString(buf, brand))
+	return &plugin.Message{Channel: channel, Data: buf.Bytes()}
+}
+
+func TestConnectedPlayer_handleClientBrand(t *testing.T) {
+	for _, channel := range []string{plugin.BrandChannelLegacy, plugin.BrandChannel} {
+		t.Run(channel, func(t *testing.T) {
+			p := newTestProxy(t, config.DefaultConfig)
+			player := &connectedPlayer{sessionHandlerDeps: &sessionHandlerDeps{
+				proxy: p, configProvider: p, eventMgr: p.event,
+			}}
+			events := make(chan *PlayerClientBrandEvent, 2)
+			p.event.Subscribe(&PlayerClientBrandEvent{}, 0, func(e event.Event) {
+				events <- e.(*PlayerClientBrandEvent)
+			})
+
+			msg := brandMessage(t, channel, "fabric")
+			require.True(t, plugin.McBrand(msg))
+			require.Empty(t, player.ClientBrand())
+
+			player.handleClientBrand(msg)
+			p.event.Wait()
+			require.Equal(t, "fabric", player.ClientBrand())
+			require.Len(t, events, 1)
+			e := <-events
+			require.Equal(t, "fabric", e.Brand())
+			require.Same(t, player, e.Player())
+
+			// Only the first brand is kept and fires the event
+			player.handleClientBrand(brandMessage(t, channel, "vanilla"))
+			p.event.Wait()
+			require.Equal(t, "fabric", player.ClientBrand())
+			require.Empty(t, events)
+		})
+	}
+}
@@ -32,8 +32,9 @@ var Plugins []Plugin
 //   - load the cfg (parse found file, flags and env vars)
 //   - make and run the proxy.Proxy that will call the Plugins init hooks.
 //
-// Script languages:
-//   - Not yet supported.
+// JavaScript:
+//   - Enable plugins in the Gate config and put .js files in the plugins directory.
+//   - See the script package for the API available to scripts.
 type Plugin struct {
 	Name string                   // The name identifying the plugin.
 	Init func(proxy *Proxy) error // The hook to initialize the plugin.
@@ -21,6 +21,7 @@ import (
 	"go.minekube.com/gate/pkg/edition/java/proxy/message"
 	"go.minekube.com/gate/pkg/gate/proto"
 	"go.minekube.com/gate/pkg/internal/addrquota"
+	"go.minekube.com/gate/pkg/internal/proxyproto"
 	"go.minekube.com/gate/pkg/runtime/event"
 	"go.minekube.com/gate/pkg/util/errs"
 	"go.minekube.com/gate/pkg/util/favicon"
@@ -32,7 +33,6 @@ import (
 // Proxy is Gate's Java edition Minecraft proxy.
 type Proxy struct {
 	log              logr.Logger
-	cfg              *config.Config
 	event            event.Manager
 	command          command.Manager
 	channelRegistrar *message.ChannelRegistrar
@@ -44,9 +44,16 @@ type Proxy struct {
 	closeListener chan struct{}
 	started       bool
 
-	shutdownReason *component.Text
-	motd           *component.Text
-	favicon        favicon.Favicon
+	reloadMu sync.Mutex // Serializes Reload
+
+	muC              sync.RWMutex // Protects following fields, replaced by Reload
+	cfg              *config.Config
+	shutdownReason   *component.Text
+	motd             *component.Text
+	forcedHostMotd   map[string]*component.Text // virtualhost pattern:motd
+	favicon          favicon.Favicon
+	connectionsQuota *addrquota.Quota
+	loginsQuota      *addrquota.Quota
 
 	muS     sync.RWMutex                 // Protects following field
 	servers map[string]*registeredServer // registered backend servers: by lower case names
@@ -55,8 +62,10 @@ type Proxy struct {
 	playerNames map[string]*connectedPlayer    // lower case usernames map
 	playerIDs   map[uuid.UUID]*connectedPlayer // uuids map
 
-	connectionsQuota *addrquota.Quota
-	loginsQuota      *addrquota.Quota
+	haProxyTrusted []*net.IPNet // upstreams trusted to send PROXY protocol headers
+	pinger         serverPinger // pings backend servers for the ping passthrough
+
+	packetInterceptors packetInterceptors
 }
 
 // Options are the options for a new Java edition Proxy.
@@ -101,14 +110,12 @@ func New(options Options) (p *Proxy, err error) {
 	}
 
 	c := options.Config
-	// Connection & login rate limiters
-	quota := c.Quota.Connections
-	if quota.Enabled {
-		p.connectionsQuota = addrquota.NewQuota(quota.OPS, quota.Burst, quota.MaxEntries)
-	}
-	quota = c.Quota.Logins
-	if quota.Enabled {
-		p.loginsQuota = addrquota.NewQuota(quota.OPS, quota.Burst, quota.MaxEntries)
+	for _, cidr := range c.HAProxyTrustedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing HA-Proxy trusted CIDR %q: %w", cidr, err)
+		}
+		p.haProxyTrusted = append(p.haProxyTrusted, ipNet)
 	}
 
 	return p, nil
@@ -149,11 +156,27 @@ func (p *Proxy) Start(ctx context.Context) error {
 	if err := p.preInit(); err != nil {
 		return fmt.Errorf("pre-initialization error: %w", err)
 	}
-	defer p.Shutdown(p.shutdownReason) // disconnects players
-	if p.cfg.Debug {
+	defer func() {
+		p.muC.RLock()
+		reason := p.shutdownReason
+		p.muC.RUnlock()
+		p.Shutdown(reason) // disconnects players
+	}()
+	c := p.config()
+	if c.Debug {
 		p.log.Info("running in debug mode")
 	}
-	return p.listenAndServe(p.cfg.Bind, stopListener)
+	go p.runHealthChecks(stopListener)
+	go p.runTabListRefresh(stopListener)
+	if c.Query.Enabled {
+		go func() {
+			addr := queryAddr(c.Bind, c.Query.Port)
+			if err := p.listenAndServeQuery(addr, stopListener); err != nil {
+				p.log.Error(err, "error serving query requests", "addr", addr)
+			}
+		}()
+	}
+	return p.listenAndServe(c.Bind, stopListener)
 }
 
 // Shutdown stops the Proxy and/or blocks until the Proxy has finished shutdown.
@@ -207,30 +230,21 @@ func (p *Proxy) Shutdown(reason component.Component) {
 
 // called before starting to actually run the proxy
 func (p *Proxy) preInit() (err error) {
-	// Load shutdown reason
-	if err = p.loadShutdownReason(); err != nil {
-		return fmt.Errorf("error loading shutdown reason: %w", err)
-	}
-	// Load status motd
-	if err = p.loadMotd(); err != nil {
-		return fmt.Errorf("error loading status motd: %w", err)
-	}
-	// Load favicon
-	if err = p.loadFavicon(); err != nil {
-		return fmt.Errorf("error loading favicon: %w", err)
+	c := p.config()
+	// Load shutdown reason, status motd, favicon and rate limiters
+	if err = p.applyConfig(c); err != nil {
+		return err
 	}
 
-	c := p.cfg
 	// Register servers
 	if len(c.Servers) != 0 {
 		p.log.Info("Registering servers...", "count", len(c.Servers))
 	}
-	for name, addr := range c.Servers {
-		pAddr, err := netutil.Parse(addr, "tcp")
+	for name, server := range c.Servers {
+		info, err := configServerInfo(name, server.Address)
 		if err != nil {
-			return fmt.Errorf("error parsing server %q address %q: %w", name, addr, err)
+			return err
 		}
-		info := NewServerInfo(name, pAddr)
 		_, err = p.Register(info)
 		if err != nil {
 			p.log.Info("Could not register server", "server", info)
@@ -246,23 +260,99 @@ func (p *Proxy) preInit() (err error) {
 	return p.initPlugins()
 }
 
+// configServerInfo returns the ServerInfo of a server from the cfg.
+func configServerInfo(name, addr string) (ServerInfo, error) {
+	pAddr, err := netutil.Parse(addr, "tcp")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing server %q address %q: %w", name, addr, err)
+	}
+	return NewServerInfo(name, pAddr), nil
+}
+
+// applyConfig loads the state derived from the cfg and makes c the cfg used by the Proxy.
+// Rate limiters are kept if their quota settings did not change.
+func (p *Proxy) applyConfig(c *config.Config) error {
+	shutdownReason, err := loadShutdownReason(c)
+	if err != nil {
+		return fmt.Errorf("error loading shutdown reason: %w", err)
+	}
+	motd, forcedHostMotd, err := loadMotd(c)
+	if err != nil {
+		return fmt.Errorf("error loading status motd: %w", err)
+	}
+	fav, err := p.loadFavicon(c)
+	if err != nil {
+		return fmt.Errorf("error loading favicon: %w", err)
+	}
+	if err = checkTabListTemplates(&c.TabList); err != nil {
+		return fmt.Errorf("error loading tab list: %w", err)
+	}
+
+	p.muC.Lock()
+	defer p.muC.Unlock()
+	prev := p.cfg
+	p.connectionsQuota = reuseQuota(p.connectionsQuota, prev.Quota.Connections, c.Quota.Connections)
+	p.loginsQuota = reuseQuota(p.loginsQuota, prev.Quota.Logins, c.Quota.Logins)
+	p.cfg = c
+	p.shutdownReason = shutdownReason
+	p.motd = motd
+	p.forcedHostMotd = forcedHostMotd
+	p.favicon = fav
+	return nil
+}
+
+// reuseQuota returns the current rate limiter if the quota settings did not change
+// so that tracked addresses are kept or else a new one for the settings.
+func reuseQuota(current *addrquota.Quota, prev, settings config.QuotaSettings) *addrquota.Quota {
+	if !settings.Enabled {
+		return nil
+	}
+	if current != nil && prev == settings {
+		return current
+	}
+	return addrquota.NewQuota(settings.OPS, settings.Burst, settings.MaxEntries)
+}
+
 // loads shutdown kick reason on proxy shutdown from the cfg
-func (p *Proxy) loadShutdownReason() (err error) {
-	c := p.cfg
+func loadShutdownReason(c *config.Config) (*component.Text, error) {
 	if len(c.ShutdownReason) == 0 {
-		return nil
+		return nil, nil
 	}
-	p.shutdownReason, err = parseTextComponentFromConfig(c.ShutdownReason)
-	return
+	return parseTextComponentFromConfig(c.ShutdownReason)
 }
 
-func (p *Proxy) loadMotd() (err error) {
-	c := p.cfg
+func loadMotd(c *config.Config) (motd *component.Text, forcedHostMotd map[string]*component.Text, err error) {
+	forcedHostMotd = make(map[string]*component.Text, len(c.Status.ForcedHostMotds))
+	for host, m := range c.Status.ForcedHostMotds {
+		forcedHostMotd[host], err = parseTextComponentFromConfig(m)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing motd of forced host %q: %w", host, err)
+		}
+	}
 	if len(c.Status.Motd) == 0 {
-		return nil
+		return nil, forcedHostMotd, nil
 	}
-	p.motd, err = parseTextComponentFromConfig(c.Status.Motd)
-	return
+	motd, err = parseTextComponentFromConfig(c.Status.Motd)
+	return motd, forcedHostMotd, err
+}
+
+// motdFor returns the motd of the forced host matching the virtual host or else the default motd.
+func (p *Proxy) motdFor(virtualHost net.Addr) *component.Text {
+	p.muC.RLock()
+	defer p.muC.RUnlock()
+	if virtualHost != nil {
+		if motd, ok := config.MatchForcedHost(p.forcedHostMotd, virtualHost.String()); ok {
+			return motd
+		}
+	}
+	return p.motd
+}
+
+// statusFavicon returns the favicon shown in the server list.
+func (p *Proxy) statusFavicon() favicon.Favicon {
+	p.muC.RLock()
+	defer p.muC.RUnlock()
+	return p.favicon
 }
 
 func parseTextComponentFromConfig(s string) (t *component.Text, err error) {
@@ -282,23 +372,22 @@ func parseTextComponentFromConfig(s string) (t *component.Text, err error) {
 	return t, nil
 }
 
-// initializes favicon from the cfg
-func (p *Proxy) loadFavicon() (err error) {
-	c := p.cfg
+// loads favicon from the cfg
+func (p *Proxy) loadFavicon(c *config.Config) (fav favicon.Favicon, err error) {
 	if len(c.Status.Favicon) == 0 {
-		return nil
+		return "", nil
 	}
 	if strings.HasPrefix(c.Status.Favicon, "data:image/") {
-		p.favicon = favicon.Favicon(c.Status.Favicon)
-		p.log.Info("Using favicon from data uri", "length", len(p.favicon))
+		fav = favicon.Favicon(c.Status.Favicon)
+		p.log.Info("Using favicon from data uri", "length", len(fav))
 	} else {
-		p.favicon, err = favicon.FromFile(c.Status.Favicon)
+		fav, err = favicon.FromFile(c.Status.Favicon)
 		if err != nil {
-			return fmt.Errorf("error reading favicon file %q: %w", c.Status.Favicon, err)
+			return "", fmt.Errorf("error reading favicon file %q: %w", c.Status.Favicon, err)
 		}
 		p.log.Info("Using favicon file", "file", c.Status.Favicon)
 	}
-	return nil
+	return fav, nil
 }
 
 func (p *Proxy) initPlugins() error {
@@ -322,10 +411,14 @@ func (p *Proxy) Command() *command.Manager {
 
 // Config returns the cfg used by the Proxy.
 func (p *Proxy) Config() config.Config {
-	return *p.cfg
+	return *p.config()
 }
 
+// config returns the cfg used by the Proxy.
+// It must not be modified since it is replaced as a whole by Reload.
 func (p *Proxy) config() *config.Config {
+	p.muC.RLock()
+	defer p.muC.RUnlock()
 	return p.cfg
 }
 
@@ -421,6 +514,8 @@ func (p *Proxy) Unregister(info ServerInfo) bool {
 		return false
 	}
 	delete(p.servers, name)
+	serverPlayersOnline.DeleteLabelValues(rs.ServerInfo().Name())
+	p.pinger.forget(name)
 
 	p.log.Info("Unregistered backend server",
 		"name", info.Name(), "addr", info.Addr())
@@ -482,8 +577,26 @@ func (p *Proxy) listenAndServe(addr string, stop <-chan struct{}) error {
 // HandleConn handles a just-accepted client connection
 // that has not had any I/O performed on it yet.
 func (p *Proxy) HandleConn(raw net.Conn) {
-	if p.connectionsQuota != nil && p.connectionsQuota.Blocked(netutil.Host(raw.RemoteAddr())) {
+	connectionAttempts.Inc()
+	p.muC.RLock()
+	cfg, connectionsQuota, loginsQuota := p.cfg, p.connectionsQuota, p.loginsQuota
+	p.muC.RUnlock()
+
+	if cfg.HAProxyProtocol && p.trustedHAProxy(raw.RemoteAddr()) {
+		// Replace the load balancer's address with the real client address
+		conn, err := proxyproto.NewConn(raw, time.Duration(cfg.ReadTimeout)*time.Millisecond)
+		if err != nil {
+			p.log.V(1).Info("Error reading PROXY protocol header, closed",
+				"remoteAddr", raw.RemoteAddr(), "err", err)
+			_ = raw.Close()
+			return
+		}
+		raw = conn
+	}
+
+	if connectionsQuota != nil && connectionsQuota.Blocked(netutil.Host(raw.RemoteAddr())) {
 		p.log.Info("Connection exceeded rate limit, closed", "remoteAddr", raw.RemoteAddr())
+		quotaRejections.WithLabelValues(quotaConnections).Inc()
 		_ = raw.Close()
 		return
 	}
@@ -498,9 +611,9 @@ func (p *Proxy) HandleConn(raw net.Conn) {
 	// Create client connection
 	conn, readLoop := netmc.NewMinecraftConn(
 		ctx, raw, proto.ServerBound,
-		time.Duration(p.cfg.ReadTimeout)*time.Millisecond,
-		time.Duration(p.cfg.ConnectionTimeout)*time.Millisecond,
-		p.cfg.Compression.Level,
+		time.Duration(cfg.ReadTimeout)*time.Millisecond,
+		time.Duration(cfg.ConnectionTimeout)*time.Millisecond,
+		cfg.Compression.Level,
 	)
 	conn.SetSessionHandler(newHandshakeSessionHandler(conn, &sessionHandlerDeps{
 		proxy:          p,
@@ -509,11 +622,29 @@ func (p *Proxy) HandleConn(raw net.Conn) {
 		configProvider: p,
 		eventMgr:       p.event,
 		authenticator:  p.authenticator,
-		loginsQuota:    p.loginsQuota,
+		loginsQuota:    loginsQuota,
 	}))
 	readLoop()
 }
 
+// trustedHAProxy returns true if addr is allowed to send a PROXY protocol header.
+// Only direct tcp connections are trusted, e.g. Connect tunnels are not.
+func (p *Proxy) trustedHAProxy(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	if len(p.haProxyTrusted) == 0 {
+		return true
+	}
+	for _, ipNet := range p.haProxyTrusted {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
 // PlayerCount returns the number of players on the proxy.
 func (p *Proxy) PlayerCount() int {
 	p.muP.RLock()
@@ -565,7 +696,7 @@ func (p *Proxy) playerByName(username string) *connectedPlayer {
 }
 
 func (p *Proxy) canRegisterConnection(player *connectedPlayer) bool {
-	c := p.cfg
+	c := p.config()
 	if c.OnlineMode && c.OnlineModeKickExistingPlayers {
 		return true
 	}
@@ -578,7 +709,7 @@ func (p *Proxy) canRegisterConnection(player *connectedPlayer) bool {
 // Attempts to register the connection with the proxy.
 func (p *Proxy) registerConnection(player *connectedPlayer) bool {
 	lowerName := strings.ToLower(player.Username())
-	c := p.cfg
+	c := p.config()
 
 retry:
 	p.muP.Lock()
@@ -616,6 +747,7 @@ retry:
 	p.playerIDs[player.ID()] = player
 	p.playerNames[lowerName] = player
 	p.muP.Unlock()
+	playersOnline.Inc()
 	return true
 }
 
@@ -626,6 +758,9 @@ func (p *Proxy) unregisterConnection(player *connectedPlayer) (found bool) {
 	_, found = p.playerIDs[player.ID()]
 	delete(p.playerNames, strings.ToLower(player.Username()))
 	delete(p.playerIDs, player.ID())
+	if found {
+		playersOnline.Dec()
+	}
 	return found
 }
 
@@ -663,8 +798,10 @@ func BroadcastMessage(sinks []MessageSink, msg component.Component) {
 //
 //
 
-func withConnectionTimeout(parent context.Context, cfg *config.Config) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(parent, time.Duration(cfg.ConnectionTimeout)*time.Millisecond)
+// withConnectionTimeout returns a context timing out after the connection timeout of the server.
+func withConnectionTimeout(parent context.Context, cfg *config.Config, server RegisteredServer) (context.Context, context.CancelFunc) {
+	timeout := cfg.ConnectionTimeoutFor(server.ServerInfo().Name())
+	return context.WithTimeout(parent, time.Duration(timeout)*time.Millisecond)
 }
 
 type (
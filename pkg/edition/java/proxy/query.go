@@ -0,0 +1,184 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.minekube.com/common/minecraft/component/codec/legacy"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/edition/java/query"
+	"go.minekube.com/gate/pkg/util/errs"
+	"go.minekube.com/gate/pkg/util/netutil"
+	gversion "go.minekube.com/gate/pkg/version"
+)
+
+// queryChallengeTTL is the duration a query challenge token is valid.
+const queryChallengeTTL = 30 * time.Second
+
+// queryMap is the map name reported by query responses.
+const queryMap = "Gate"
+
+// listenAndServeQuery answers GameSpy4 query requests on addr until stop channel receives.
+func (p *Proxy) listenAndServeQuery(addr string, stop <-chan struct{}) error {
+	select {
+	case <-stop:
+		return nil
+	default:
+	}
+
+	ln, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ln.Close() }()
+	go func() { <-stop; _ = ln.Close() }()
+
+	defer p.log.Info("Stopped listening for query requests")
+	p.log.Info("Listening for query requests", "addr", addr)
+
+	challenges := newQueryChallenges()
+	buf := make([]byte, 1024)
+	for {
+		n, raddr, err := ln.ReadFrom(buf)
+		if err != nil {
+			if errs.IsConnClosedErr(err) {
+				// Listener was closed
+				return nil
+			}
+			return fmt.Errorf("error reading query request: %w", err)
+		}
+		if err = p.handleQuery(ln, raddr, buf[:n], challenges); err != nil {
+			p.log.V(1).Info("error handling query request", "remoteAddr", raddr, "err", err)
+		}
+	}
+}
+
+func (p *Proxy) handleQuery(
+	ln net.PacketConn, raddr net.Addr,
+	b []byte, challenges *queryChallenges,
+) error {
+	req, err := query.ParseRequest(b)
+	if err != nil {
+		return err
+	}
+	host := netutil.Host(raddr)
+
+	res := new(bytes.Buffer)
+	switch req.Type {
+	case query.HandshakeType:
+		err = query.WriteHandshake(res, req.SessionID, challenges.issue(host))
+	case query.StatType:
+		if !challenges.valid(host, req.Challenge) {
+			return errors.New("invalid challenge token")
+		}
+		r := p.newQueryResponse()
+		if req.Full {
+			err = r.WriteFull(res, req.SessionID)
+		} else {
+			err = r.WriteBasic(res, req.SessionID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = ln.WriteTo(res.Bytes(), raddr)
+	return err
+}
+
+// newQueryResponse creates a query response with the current state of the proxy.
+func (p *Proxy) newQueryResponse() *query.Response {
+	cfg := p.config()
+	var motd string
+	if m := p.motdFor(nil); m != nil {
+		b := new(strings.Builder)
+		if err := (&legacy.Legacy{}).Marshal(b, m); err == nil {
+			motd = b.String()
+		}
+	}
+
+	host, port := netutil.HostPort(netutil.NewAddr(cfg.Bind, "tcp"))
+	r := &query.Response{
+		Hostname:      motd,
+		GameVersion:   version.SupportedVersionsString,
+		Map:           queryMap,
+		Players:       p.PlayerCount(),
+		MaxPlayers:    cfg.Status.ShowMaxPlayers,
+		ProxyHost:     host,
+		ProxyPort:     port,
+		ServerVersion: serverVersion(),
+	}
+	for _, player := range p.Players() {
+		r.PlayerNames = append(r.PlayerNames, player.Username())
+	}
+	if cfg.Query.ShowPlugins {
+		for _, pl := range Plugins {
+			r.Plugins = append(r.Plugins, pl.Name)
+		}
+	}
+	return r
+}
+
+// serverVersion returns Gate's software name and version.
+func serverVersion() string {
+	if v := gversion.String(); v != "" {
+		return "Gate " + v
+	}
+	return "Gate"
+}
+
+// queryAddr returns the address to listen for query requests,
+// which is the host of bind address combined with the query port.
+func queryAddr(bind string, port int) string {
+	return net.JoinHostPort(netutil.Host(netutil.NewAddr(bind, "udp")), strconv.Itoa(port))
+}
+
+// queryChallenges stores issued challenge tokens by host.
+type queryChallenges struct {
+	mu        sync.Mutex
+	tokens    map[string]queryChallenge
+	lastPurge time.Time
+}
+
+type queryChallenge struct {
+	token   int32
+	expires time.Time
+}
+
+func newQueryChallenges() *queryChallenges {
+	return &queryChallenges{tokens: map[string]queryChallenge{}, lastPurge: time.Now()}
+}
+
+// issue generates a new challenge token for the host.
+func (c *queryChallenges) issue(host string) int32 {
+	now := time.Now()
+	token := rand.Int31()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastPurge) > queryChallengeTTL {
+		// Remove expired tokens
+		for h, ch := range c.tokens {
+			if now.After(ch.expires) {
+				delete(c.tokens, h)
+			}
+		}
+		c.lastPurge = now
+	}
+	c.tokens[host] = queryChallenge{token: token, expires: now.Add(queryChallengeTTL)}
+	return token
+}
+
+// valid returns true if the token was issued for the host and has not expired.
+func (c *queryChallenges) valid(host string, token int32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch, ok := c.tokens[host]
+	return ok && ch.token == token && time.Now().Before(ch.expires)
+}
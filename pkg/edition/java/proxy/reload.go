@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/multierr"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/util/errs"
+)
+
+// Reload validates the config and applies it to the running Proxy without a restart.
+//
+// Servers added to or removed from the config are registered or unregistered,
+// players already connected to a removed server stay connected. Changes to the
+// try list, forced hosts, status motd and favicon, quotas, compression and other
+// settings read per connection apply to new connections.
+//
+// Settings only read when the Proxy starts, like the bind address, keep their
+// current value and are logged as requiring a restart.
+//
+// A ConfigUpdateEvent is fired after the config was applied.
+func (p *Proxy) Reload(cfg *config.Config) error {
+	if cfg == nil {
+		return errs.ErrMissingConfig
+	}
+	warns, validationErrs := cfg.Validate()
+	for _, w := range warns {
+		p.log.Info("config validation warn", "warn", w.Error())
+	}
+	if err := multierr.Combine(validationErrs...); err != nil {
+		return fmt.Errorf("config validation errors (errors: %d, warns: %d): %w",
+			len(validationErrs), len(warns), err)
+	}
+
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	p.closeMu.Lock()
+	started := p.started
+	p.closeMu.Unlock()
+	if !started {
+		return errors.New("proxy is not running")
+	}
+
+	prev := p.config()
+	next := *cfg // copy to not be affected by later modifications of cfg
+	if changed := keepStartupSettings(prev, &next); len(changed) != 0 {
+		p.log.Info("Some config changes require a restart to take effect", "settings", changed)
+	}
+	if err := p.applyConfig(&next); err != nil {
+		return err
+	}
+	p.reloadServers(prev.Servers, next.Servers)
+
+	p.log.Info("Reloaded config")
+	p.event.Fire(&ConfigUpdateEvent{
+		config:     &next,
+		prevConfig: prev,
+	})
+	return nil
+}
+
+// keepStartupSettings resets the settings of next that are only read when
+// the Proxy starts to their value in prev and returns the names of the changed ones.
+func keepStartupSettings(prev, next *config.Config) (changed []string) {
+	keep(&changed, "bind", prev.Bind, &next.Bind)
+	keep(&changed, "debug", prev.Debug, &next.Debug)
+	keep(&changed, "query.enabled", prev.Query.Enabled, &next.Query.Enabled)
+	keep(&changed, "query.port", prev.Query.Port, &next.Query.Port)
+	keep(&changed, "haProxyProtocol", prev.HAProxyProtocol, &next.HAProxyProtocol)
+	keep(&changed, "haProxyTrustedCIDRs", prev.HAProxyTrustedCIDRs, &next.HAProxyTrustedCIDRs)
+	keep(&changed, "builtinCommands", prev.BuiltinCommands, &next.BuiltinCommands)
+	return changed
+}
+
+func keep[T any](changed *[]string, name string, prev T, next *T) {
+	if !reflect.DeepEqual(prev, *next) {
+		*changed = append(*changed, name)
+		*next = prev
+	}
+}
+
+// reloadServers unregisters servers removed from the config and registers added ones.
+// Servers whose address changed are replaced. Servers registered by plugins
+// under the name of a config server are left untouched.
+func (p *Proxy) reloadServers(prev, next map[string]config.Server) {
+	for name, server := range prev {
+		if next[name].Address == server.Address {
+			continue
+		}
+		info, err := configServerInfo(name, server.Address)
+		if err != nil {
+			continue // was never registered
+		}
+		p.Unregister(info)
+	}
+	for name, server := range next {
+		if prev[name].Address == server.Address {
+			continue
+		}
+		info, err := configServerInfo(name, server.Address)
+		if err != nil {
+			p.log.Info("Could not register server", "server", name, "error", err)
+			continue
+		}
+		if _, err = p.Register(info); err != nil {
+			p.log.Info("Could not register server", "server", info, "error", err)
+		}
+	}
+}
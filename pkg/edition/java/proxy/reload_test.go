@@ -0,0 +1,87 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+func TestProxy_Reload(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.Servers = map[string]config.Server{
+		"lobby": {Address: "localhost:25566"},
+		"old":   {Address: "localhost:25567"},
+	}
+	cfg.Try = []string{"lobby", "old"}
+	p := newTestProxy(t, cfg)
+	require.Error(t, p.Reload(&cfg), "proxy is not running")
+
+	p.started = true
+	require.NoError(t, p.preInit())
+	lobby := p.Server("lobby")
+	require.NotNil(t, lobby)
+	require.NotNil(t, p.Server("old"))
+
+	events := make(chan *ConfigUpdateEvent, 1)
+	p.event.Subscribe(&ConfigUpdateEvent{}, 0, func(e event.Event) {
+		events <- e.(*ConfigUpdateEvent)
+	})
+
+	next := cfg
+	next.Servers = map[string]config.Server{
+		"lobby": {Address: "localhost:25566"},
+		"new":   {Address: "localhost:25568"},
+	}
+	next.Try = []string{"new", "lobby"}
+	next.Status.Motd = "§aReloaded"
+	next.Bind = "0.0.0.0:25577"
+	next.Debug = true
+	require.NoError(t, p.Reload(&next))
+	p.event.Wait()
+
+	// Servers are registered and unregistered, unchanged ones are kept
+	require.Same(t, lobby, p.Server("lobby"))
+	require.Nil(t, p.Server("old"))
+	require.NotNil(t, p.Server("new"))
+
+	// Per connection settings are applied
+	c := p.config()
+	require.Equal(t, next.Try, c.Try)
+	motd, _, err := loadMotd(&next)
+	require.NoError(t, err)
+	require.Equal(t, motd, p.motdFor(nil))
+
+	// Startup settings are kept
+	require.Equal(t, cfg.Bind, c.Bind)
+	require.Equal(t, cfg.Debug, c.Debug)
+	require.Equal(t, "0.0.0.0:25577", next.Bind, "must not modify the passed config")
+
+	require.Len(t, events, 1)
+	e := <-events
+	require.Same(t, c, e.Config())
+	require.Equal(t, cfg.Try, e.PrevConfig().Try)
+
+	// Invalid configs are rejected
+	invalid := next
+	invalid.Bind = ""
+	require.Error(t, p.Reload(&invalid))
+	require.Same(t, c, p.config())
+}
+
+func TestKeepStartupSettings(t *testing.T) {
+	prev := config.DefaultConfig
+	next := prev
+	require.Empty(t, keepStartupSettings(&prev, &next))
+
+	next.Bind = "0.0.0.0:25577"
+	next.Query.Port = 25578
+	next.Try = []string{"lobby"}
+	changed := keepStartupSettings(&prev, &next)
+	require.Equal(t, []string{"bind", "query.port"}, changed)
+	require.Equal(t, prev.Bind, next.Bind)
+	require.Equal(t, prev.Query.Port, next.Query.Port)
+	require.Equal(t, []string{"lobby"}, next.Try)
+}
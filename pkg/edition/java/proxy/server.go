@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.minekube.com/common/minecraft/component"
 	"go.minekube.com/gate/pkg/edition/java/netmc"
 	"go.minekube.com/gate/pkg/edition/java/proxy/phase"
 	"go.minekube.com/gate/pkg/gate/proto"
@@ -18,10 +20,12 @@ import (
 
 	"go.minekube.com/gate/pkg/edition/java/config"
 	"go.minekube.com/gate/pkg/edition/java/forge"
+	"go.minekube.com/gate/pkg/edition/java/profile"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	"go.minekube.com/gate/pkg/edition/java/proxy/message"
+	"go.minekube.com/gate/pkg/internal/proxyproto"
 	"go.minekube.com/gate/pkg/util/netutil"
 	"go.minekube.com/gate/pkg/util/uuid"
 )
@@ -35,6 +39,8 @@ type Players interface {
 type players struct {
 	mu   sync.RWMutex // Protects following fields
 	list map[uuid.UUID]*connectedPlayer
+
+	online prometheus.Gauge // nil-able, tracks the size of list
 }
 
 func newPlayers() *players {
@@ -75,20 +81,32 @@ func PlayersToSlice[R any](p Players) []R {
 
 func (p *players) add(players ...*connectedPlayer) {
 	p.mu.Lock()
+	before := len(p.list)
 	for _, player := range players {
 		p.list[player.ID()] = player
 	}
+	p.trackOnline(before)
 	p.mu.Unlock()
 }
 
 func (p *players) remove(players ...*connectedPlayer) {
 	p.mu.Lock()
+	before := len(p.list)
 	for _, player := range players {
 		delete(p.list, player.ID())
 	}
+	p.trackOnline(before)
 	p.mu.Unlock()
 }
 
+// trackOnline adds the change of the list size to the online gauge.
+// Must be called with the lock held.
+func (p *players) trackOnline(before int) {
+	if p.online != nil {
+		p.online.Add(float64(len(p.list) - before))
+	}
+}
+
 //
 //
 //
@@ -150,6 +168,9 @@ func (i *serverInfo) String() string { return fmt.Sprintf("%s (%s)", i.name, i.a
 type RegisteredServer interface {
 	ServerInfo() ServerInfo
 	Players() Players // The players connected to the server on THIS proxy.
+	// Status returns the status of the server as determined by the last health check.
+	// It is the zero ServerStatus if the server was not checked yet.
+	Status() ServerStatus
 }
 
 // RegisteredServerEqual returns true if RegisteredServer a and b are equal.
@@ -161,10 +182,15 @@ func RegisteredServerEqual(a, b RegisteredServer) bool {
 type registeredServer struct {
 	info    ServerInfo
 	players *players
+
+	mu     sync.RWMutex // Protects following field
+	status ServerStatus
 }
 
 func newRegisteredServer(info ServerInfo) *registeredServer {
-	return &registeredServer{info: info, players: newPlayers()}
+	players := newPlayers()
+	players.online = serverPlayersOnline.WithLabelValues(info.Name())
+	return &registeredServer{info: info, players: players}
 }
 
 func (r *registeredServer) ServerInfo() ServerInfo {
@@ -175,6 +201,21 @@ func (r *registeredServer) Players() Players {
 	return r.players
 }
 
+// Status returns the status of the server as determined by the last health check.
+func (r *registeredServer) Status() ServerStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// setStatus sets the status and returns the previous one.
+func (r *registeredServer) setStatus(status ServerStatus) (prev ServerStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, r.status = r.status, status
+	return prev
+}
+
 var _ RegisteredServer = (*registeredServer)(nil)
 
 // BroadcastPluginMessage sends the plugin message to all players on the server.
@@ -214,9 +255,10 @@ type serverConnection struct {
 	lastPingSent            atomic.Int64              // unix millis
 	activeDimensionRegistry *packet.DimensionRegistry // updated by packet.JoinGame
 
-	mu         sync.RWMutex        // Protects following fields
-	connection netmc.MinecraftConn // the backend server connection
-	connPhase  phase.BackendConnectionPhase
+	mu             sync.RWMutex        // Protects following fields
+	connection     netmc.MinecraftConn // the backend server connection
+	connPhase      phase.BackendConnectionPhase
+	header, footer component.Component // tab list header and footer sent by the backend, nil-able
 }
 
 func newServerConnection(server *registeredServer, player *connectedPlayer) *serverConnection {
@@ -300,6 +342,20 @@ type ServerDialer interface {
 }
 
 func (s *serverConnection) dial(ctx context.Context) (net.Conn, error) {
+	conn, err := s.dialServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.sendProxyProtocol() {
+		if err = s.writeProxyHeader(conn); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("error writing PROXY protocol header: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (s *serverConnection) dialServer(ctx context.Context) (net.Conn, error) {
 	var (
 		sd ServerDialer
 		ok bool
@@ -313,11 +369,34 @@ func (s *serverConnection) dial(ctx context.Context) (net.Conn, error) {
 	return sd.Dial(ctx, s.player)
 }
 
+// sendProxyProtocol returns true if the server is configured
+// to receive a PROXY protocol header on new connections.
+func (s *serverConnection) sendProxyProtocol() bool {
+	return s.config().ProxyProtocolFor(s.Server().ServerInfo().Name())
+}
+
+// writeProxyHeader writes a PROXY protocol v2 header with the player's address
+// to the server connection before anything else is sent.
+func (s *serverConnection) writeProxyHeader(conn net.Conn) error {
+	b, err := proxyproto.NewHeader(s.player.RemoteAddr(), conn.RemoteAddr()).Format()
+	if err != nil {
+		return err
+	}
+	if timeout := time.Duration(s.config().ConnectionTimeoutFor(s.server.ServerInfo().Name())) * time.Millisecond; timeout > 0 {
+		if err = conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+		defer func() { _ = conn.SetWriteDeadline(time.Time{}) }()
+	}
+	_, err = conn.Write(b)
+	return err
+}
+
 // HandshakeAddresser provides the ServerAddress sent with the packet.Handshake when a player joins the server
 // implementing this interface.
 // A ServerInfo of a registered server or a RegisteredServer can implement this interface.
 // If no ServerInfo or RegisteredServer implements this interface the ServerInfo.Addr the default ServerAddress is used
-// or the BungeeCord forwarding scheme if the proxy is in cfg.LegacyForwardingMode.
+// or the BungeeCord forwarding scheme if the proxy is in a legacy forwarding mode.
 type HandshakeAddresser interface {
 	HandshakeAddr(defaultPlayerVirtualHost string, player Player) (newPlayerVirtualHost string)
 }
@@ -327,7 +406,7 @@ func (s *serverConnection) handshakeAddr(vHost string, player Player) string {
 	var ok bool
 	if ha, ok = s.Server().ServerInfo().(HandshakeAddresser); !ok {
 		if ha, ok = s.Server().(HandshakeAddresser); !ok {
-			if s.config().Forwarding.Mode == config.LegacyForwardingMode {
+			if s.forwarding().Mode.Legacy() {
 				return s.createLegacyForwardingAddress()
 			}
 		}
@@ -356,11 +435,12 @@ func (s *serverConnection) connect(ctx context.Context) (result *connectionResul
 		context.Background(),
 		logr.FromContextOrDiscard(s.player.MinecraftConn.Context()),
 	)
+	cfg, name := s.config(), s.server.ServerInfo().Name()
 	serverMc, readLoop := netmc.NewMinecraftConn(
 		logCtx, conn, proto.ClientBound,
-		time.Duration(s.config().ReadTimeout)*time.Millisecond,
-		time.Duration(s.config().ConnectionTimeout)*time.Millisecond,
-		s.config().Compression.Level,
+		time.Duration(cfg.ReadTimeoutFor(name))*time.Millisecond,
+		time.Duration(cfg.ConnectionTimeoutFor(name))*time.Millisecond,
+		cfg.Compression.Level,
 	)
 	resultChan := make(chan *connResponse, 1)
 	serverMc.SetSessionHandler(newBackendLoginSessionHandler(s, &connRequestCxt{
@@ -406,6 +486,7 @@ func (s *serverConnection) connect(ctx context.Context) (result *connectionResul
 	err = serverMc.WritePacket(&packet.ServerLogin{
 		Username:  s.player.Username(),
 		PlayerKey: s.player.IdentifiedKey(),
+		HolderID:  s.player.ID(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error writing ServerLogin packet to server connection: %w", err)
@@ -429,7 +510,7 @@ func (s *serverConnection) createLegacyForwardingAddress() string {
 	b.WriteString("\000")
 	b.WriteString(s.player.profile.ID.Undashed())
 	b.WriteString("\000")
-	props, err := json.Marshal(s.player.profile.Properties)
+	props, err := json.Marshal(s.forwardedProperties())
 	if err != nil { // should never happen
 		panic(err)
 	}
@@ -437,6 +518,29 @@ func (s *serverConnection) createLegacyForwardingAddress() string {
 	return b.String()
 }
 
+// bungeeGuardTokenProperty is the profile property BungeeGuard
+// protected servers expect the proxy's secret token in.
+const bungeeGuardTokenProperty = "bungeeguard-token"
+
+// forwardedProperties returns the player's profile properties to forward in the handshake
+// address with the BungeeGuard token of the server added in bungeeguard mode.
+func (s *serverConnection) forwardedProperties() []profile.Property {
+	forwarding := s.forwarding()
+	if forwarding.Mode != config.BungeeGuardForwardingMode {
+		return s.player.profile.Properties
+	}
+	props := make([]profile.Property, 0, len(s.player.profile.Properties)+1)
+	for _, prop := range s.player.profile.Properties {
+		if prop.Name != bungeeGuardTokenProperty { // must never be spoofed
+			props = append(props, prop)
+		}
+	}
+	if token := forwarding.BungeeGuardToken; token != "" {
+		props = append(props, profile.Property{Name: bungeeGuardTokenProperty, Value: token})
+	}
+	return props
+}
+
 // Returns the active backend server connection or false if inactive.
 func (s *serverConnection) ensureConnected() (backend netmc.MinecraftConn, connected bool) {
 	if s == nil {
@@ -492,3 +596,8 @@ func (s *serverConnection) completeJoin() {
 func (s *serverConnection) config() *config.Config {
 	return s.player.config()
 }
+
+// forwarding returns the player info forwarding settings for the server.
+func (s *serverConnection) forwarding() config.Forwarding {
+	return s.config().ForwardingFor(s.server.ServerInfo().Name())
+}
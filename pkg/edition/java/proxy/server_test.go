@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/profile"
+	"go.minekube.com/gate/pkg/util/uuid"
+)
+
+// testConn is a netmc.MinecraftConn with a fixed remote address.
+type testConn struct {
+	netmc.MinecraftConn
+	addr net.Addr
+}
+
+func (c *testConn) RemoteAddr() net.Addr { return c.addr }
+
+func newTestServerConnection(t *testing.T, cfg config.Config, props []profile.Property) *serverConnection {
+	p := newTestProxy(t, cfg)
+	player := &connectedPlayer{
+		MinecraftConn:      &testConn{addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 50000}},
+		sessionHandlerDeps: &sessionHandlerDeps{proxy: p, configProvider: p},
+		log:                logr.Discard(),
+		profile:            &profile.GameProfile{ID: uuid.New(), Name: "test", Properties: props},
+	}
+	server := newRegisteredServer(NewServerInfo("lobby", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25566}))
+	return newServerConnection(server, player)
+}
+
+func TestServerConnection_createLegacyForwardingAddress(t *testing.T) {
+	props := []profile.Property{
+		{Name: "textures", Value: "skin", Signature: "sig"},
+		{Name: bungeeGuardTokenProperty, Value: "spoofed"},
+	}
+	tests := []struct {
+		name      string
+		mode      config.ForwardingMode
+		token     string
+		wantProps []profile.Property
+	}{
+		{
+			name:      "legacy",
+			mode:      config.LegacyForwardingMode,
+			wantProps: props,
+		},
+		{
+			name:  "bungeeguard",
+			mode:  config.BungeeGuardForwardingMode,
+			token: "secret",
+			wantProps: []profile.Property{
+				{Name: "textures", Value: "skin", Signature: "sig"},
+				{Name: bungeeGuardTokenProperty, Value: "secret"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig
+			cfg.Forwarding = config.Forwarding{Mode: tt.mode, BungeeGuardToken: tt.token}
+			s := newTestServerConnection(t, cfg, props)
+
+			parts := strings.Split(s.createLegacyForwardingAddress(), "\000")
+			require.Len(t, parts, 4)
+			require.Equal(t, "127.0.0.1:25566", parts[0])
+			require.Equal(t, "10.0.0.1", parts[1])
+			require.Equal(t, s.player.profile.ID.Undashed(), parts[2])
+
+			var got []profile.Property
+			require.NoError(t, json.Unmarshal([]byte(parts[3]), &got))
+			require.Equal(t, tt.wantProps, got)
+		})
+	}
+}
+
+func TestServerConnection_forwardedProperties_serverToken(t *testing.T) {
+	cfg := config.DefaultConfig
+	cfg.Forwarding = config.Forwarding{Mode: config.BungeeGuardForwardingMode, BungeeGuardToken: "global"}
+	cfg.Servers = map[string]config.Server{"lobby": {
+		Address:    "127.0.0.1:25566",
+		Forwarding: config.ServerForwarding{BungeeGuardToken: "lobby"},
+	}}
+	s := newTestServerConnection(t, cfg, nil)
+	require.Equal(t, []profile.Property{{Name: bungeeGuardTokenProperty, Value: "lobby"}}, s.forwardedProperties())
+}
@@ -0,0 +1,217 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
+	"go.minekube.com/gate/pkg/edition/java/proto/state"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// Handles communication with a 1.20.2+ backend server in the config state.
+type backendConfigSessionHandler struct {
+	serverConn     *serverConnection
+	requestCtx     *connRequestCxt  // nil if the connected server started the reconfiguration
+	previousServer RegisteredServer // nil-able, the server the player was connected to before
+	listenDoneCtx  chan struct{}
+	log            logr.Logger
+
+	nopSessionHandler
+}
+
+var _ netmc.SessionHandler = (*backendConfigSessionHandler)(nil)
+
+func newBackendConfigSessionHandler(
+	serverConn *serverConnection,
+	requestCtx *connRequestCxt,
+) *backendConfigSessionHandler {
+	return &backendConfigSessionHandler{
+		serverConn: serverConn,
+		requestCtx: requestCtx,
+		log:        serverConn.log.WithName("backendConfigSession"),
+	}
+}
+
+func (b *backendConfigSessionHandler) Activated() {
+	if b.requestCtx == nil {
+		return
+	}
+	b.listenDoneCtx = make(chan struct{})
+	go func() {
+		select {
+		case <-b.listenDoneCtx:
+		case <-b.requestCtx.Done():
+			// We must check again since request context
+			// may be canceled before Deactivated() was run.
+			select {
+			case <-b.listenDoneCtx:
+				return
+			default:
+				b.requestCtx.result(nil, errors.New(
+					"context deadline exceeded while configuring player for backend server"))
+				b.serverConn.disconnect()
+			}
+		}
+	}()
+}
+
+func (b *backendConfigSessionHandler) Deactivated() {
+	if b.listenDoneCtx != nil {
+		close(b.listenDoneCtx)
+	}
+}
+
+func (b *backendConfigSessionHandler) HandlePacket(pc *proto.PacketContext) {
+	if !pc.KnownPacket {
+		b.forwardToPlayer(pc)
+		return
+	}
+
+	if !b.shouldHandle() {
+		return
+	}
+	switch p := pc.Packet.(type) {
+	case *packet.KeepAlive:
+		// The player is not in the play state, so we answer on its behalf.
+		_ = b.serverConn.conn().WritePacket(p)
+	case *packet.Disconnect:
+		b.handleDisconnect(p)
+	case *plugin.Message:
+		b.handlePluginMessage(p, pc)
+	case *config.FinishedUpdate:
+		b.handleFinishedUpdate()
+	default:
+		b.forwardToPlayer(pc)
+	}
+}
+
+func (b *backendConfigSessionHandler) shouldHandle() bool {
+	if b.serverConn.active() {
+		return true
+	}
+	// Obsolete connection
+	b.serverConn.disconnect()
+	return false
+}
+
+func (b *backendConfigSessionHandler) forwardToPlayer(pc *proto.PacketContext) {
+	_ = b.serverConn.player.Write(pc.Payload)
+}
+
+func (b *backendConfigSessionHandler) handleDisconnect(p *packet.Disconnect) {
+	if b.requestCtx == nil {
+		b.serverConn.disconnect()
+		b.serverConn.player.handleDisconnect(b.serverConn.server, p, true)
+		return
+	}
+	result := disconnectResultForPacket(b.log.V(1), p, b.serverConn.player.Protocol(), b.serverConn.server, true)
+	b.requestCtx.result(result, nil)
+	b.serverConn.disconnect()
+}
+
+func (b *backendConfigSessionHandler) handlePluginMessage(p *plugin.Message, pc *proto.PacketContext) {
+	if plugin.IsRegister(p) {
+		b.serverConn.player.pluginChannelsMu.Lock()
+		b.serverConn.player.pluginChannels.Insert(plugin.Channels(p)...)
+		b.serverConn.player.pluginChannelsMu.Unlock()
+	} else if plugin.IsUnregister(p) {
+		b.serverConn.player.pluginChannelsMu.Lock()
+		b.serverConn.player.pluginChannels.Delete(plugin.Channels(p)...)
+		b.serverConn.player.pluginChannelsMu.Unlock()
+	} else if plugin.McBrand(p) {
+		_ = b.serverConn.player.WritePacket(plugin.RewriteMinecraftBrand(p, b.serverConn.player.Protocol()))
+		return
+	}
+	b.forwardToPlayer(pc)
+}
+
+// handleFinishedUpdate waits for the player to finish the configuration
+// and moves the backend server connection into the play state.
+func (b *backendConfigSessionHandler) handleFinishedUpdate() {
+	smc, ok := b.serverConn.ensureConnected()
+	if !ok {
+		return
+	}
+	player := b.serverConn.player
+
+	configHandler, ok := player.SessionHandler().(*clientConfigSessionHandler)
+	if !ok {
+		b.fail(errors.New("player is not in the config state"))
+		return
+	}
+	done, err := configHandler.handleBackendFinishUpdate()
+	if err != nil {
+		b.fail(fmt.Errorf("error sending finished update to player: %w", err))
+		return
+	}
+
+	// Block reading from the backend server until the player switched to the
+	// play state, so we don't forward play packets to a configuring player.
+	var requestDone <-chan struct{}
+	if b.requestCtx != nil {
+		requestDone = b.requestCtx.Done()
+	}
+	select {
+	case <-done:
+	case <-requestDone:
+		b.fail(errors.New("context deadline exceeded while waiting for player to finish configuration"))
+		return
+	case <-player.Context().Done():
+		return
+	}
+
+	// Acknowledge the finished update while still in the config state.
+	if err = smc.WritePacket(&config.FinishedUpdate{}); err != nil {
+		b.fail(fmt.Errorf("error sending finished update to backend server: %w", err))
+		return
+	}
+	smc.SetState(state.Play)
+
+	if b.serverConn == player.connectedServer() {
+		// The connected server reconfigured the player and continues with the play state.
+		backendPlay, err := newBackendPlaySessionHandler(b.serverConn)
+		if err != nil {
+			b.fail(fmt.Errorf("error creating backend play session handler: %w", err))
+			return
+		}
+		smc.SetSessionHandler(backendPlay)
+		return
+	}
+
+	// Wait for the JoinGame packet to complete the server switch.
+	transition := newBackendTransitionSessionHandler(b.serverConn, b.requestCtx, player.eventMgr, player.proxy)
+	transition.previousServer = b.previousServer
+	smc.SetSessionHandler(transition)
+}
+
+func (b *backendConfigSessionHandler) fail(err error) {
+	if b.requestCtx == nil {
+		b.log.Error(err, "unable to reconfigure player, disconnecting")
+		b.serverConn.player.Disconnect(internalServerConnectionError)
+		return
+	}
+	b.requestCtx.result(nil, err)
+	b.serverConn.disconnect()
+}
+
+func (b *backendConfigSessionHandler) Disconnected() {
+	if b.requestCtx != nil {
+		b.requestCtx.result(nil, errors.New("unexpectedly disconnected from remote server"))
+		return
+	}
+	if b.serverConn.gracefulDisconnect.Load() {
+		return
+	}
+	if b.serverConn.player.proxy.Config().FailoverOnUnexpectedServerDisconnect {
+		b.serverConn.player.handleDisconnectWithReason(b.serverConn.server,
+			internalServerConnectionError, true)
+	} else {
+		b.serverConn.player.Disconnect(internalServerConnectionError)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"reflect"
 
 	"go.minekube.com/common/minecraft/component"
@@ -21,6 +22,7 @@ import (
 	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/state"
 	protoutil "go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
 	"go.minekube.com/gate/pkg/gate/proto"
 	"go.minekube.com/gate/pkg/util/errs"
 	"go.minekube.com/gate/pkg/util/netutil"
@@ -124,8 +126,8 @@ func (b *backendLoginSessionHandler) handleLoginPluginMessage(p *packet.LoginPlu
 	if !ok {
 		return
 	}
-	cfg := b.config()
-	if cfg.Forwarding.Mode == config.VelocityForwardingMode && p.Channel == velocityIpForwardingChannel {
+	forwarding := b.serverConn.forwarding()
+	if forwarding.Mode == config.VelocityForwardingMode && p.Channel == velocityIpForwardingChannel {
 
 		requestedForwardingVersion := velocityDefaultForwardingVersion
 		// Check version
@@ -134,7 +136,7 @@ func (b *backendLoginSessionHandler) handleLoginPluginMessage(p *packet.LoginPlu
 		}
 
 		forwardingData, err := createVelocityForwardingData(
-			[]byte(cfg.Forwarding.VelocitySecret),
+			[]byte(forwarding.VelocitySecret),
 			netutil.Host(b.serverConn.Player().RemoteAddr()),
 			b.serverConn.player, requestedForwardingVersion,
 		)
@@ -304,7 +306,7 @@ var velocityIpForwardingFailure = &component.Text{
 }
 
 func (b *backendLoginSessionHandler) handleServerLoginSuccess() {
-	if b.config().Forwarding.Mode == config.VelocityForwardingMode && !b.informationForwarded.Load() {
+	if b.serverConn.forwarding().Mode == config.VelocityForwardingMode && !b.informationForwarded.Load() {
 		b.requestCtx.result(disconnectResult(velocityIpForwardingFailure, b.serverConn.server, true), nil)
 		b.serverConn.disconnect()
 		return
@@ -318,14 +320,60 @@ func (b *backendLoginSessionHandler) handleServerLoginSuccess() {
 	if !ok {
 		return
 	}
+	if serverMc.Protocol().GreaterEqual(version.Minecraft_1_20_2) {
+		b.switchToConfig(serverMc)
+		return
+	}
 	serverMc.SetState(state.Play)
 
 	// Switch to the transition handler.
 	serverMc.SetSessionHandler(newBackendTransitionSessionHandler(b.serverConn, b.requestCtx, b.eventMgr, b.proxy))
 }
 
+// switchToConfig moves a 1.20.2+ backend server connection into the config state
+// and, if the player is in the play state, also the player.
+func (b *backendLoginSessionHandler) switchToConfig(serverMc netmc.MinecraftConn) {
+	if err := serverMc.WritePacket(&packet.LoginAcknowledged{}); err != nil {
+		b.requestCtx.result(nil, fmt.Errorf("error acknowledging login: %w", err))
+		b.serverConn.disconnect()
+		return
+	}
+	serverMc.SetState(state.Config)
+	configHandler := newBackendConfigSessionHandler(b.serverConn, b.requestCtx)
+	serverMc.SetSessionHandler(configHandler)
+
+	player := b.serverConn.player
+	if settings := player.clientSettings(); settings != nil {
+		_ = serverMc.WritePacket(settings)
+	}
+
+	switch h := player.SessionHandler().(type) {
+	case *clientPlaySessionHandler:
+		// Block reading from the backend server until
+		// the player switched to the config state.
+		previousServer, done, err := h.switchToConfig()
+		if err != nil {
+			b.requestCtx.result(nil, fmt.Errorf("error switching player to config state: %w", err))
+			b.serverConn.disconnect()
+			return
+		}
+		configHandler.previousServer = previousServer
+		select {
+		case <-done:
+		case <-b.requestCtx.Done():
+			b.requestCtx.result(nil, errors.New(
+				"context deadline exceeded while waiting for player to switch to config state"))
+			b.serverConn.disconnect()
+		case <-player.Context().Done():
+		}
+	case *clientConfigSessionHandler:
+		// The player is joining its initial server.
+		h.flushQueuedPluginMessages(serverMc)
+	}
+}
+
 func (b *backendLoginSessionHandler) Disconnected() {
-	if b.config().Forwarding.Mode == config.LegacyForwardingMode {
+	if b.serverConn.forwarding().Mode.Legacy() {
 		b.requestCtx.result(nil, errs.NewSilentErr(`The connection to the remote server was unexpectedly closed.
 This is usually because the remote server does not have BungeeCord IP forwarding correctly enabled.`))
 	} else {
@@ -45,6 +45,14 @@ func newBackendPlaySessionHandler(serverConn *serverConnection) (netmc.SessionHa
 }
 
 func (b *backendPlaySessionHandler) HandlePacket(pc *proto.PacketContext) {
+	smc, ok := b.serverConn.ensureConnected()
+	if !ok {
+		// Obsolete connection, drop the packet instead of forwarding it unintercepted
+		return
+	}
+	if !b.proxy().interceptPacket(smc, pc, b.serverConn.player, b.serverConn) {
+		return
+	}
 	if !pc.KnownPacket {
 		// forward unknown packet to player
 		b.forwardToPlayer(pc, nil)
@@ -66,12 +74,18 @@ func (b *backendPlaySessionHandler) HandlePacket(pc *proto.PacketContext) {
 		b.playerSessionHandler.handleTabCompleteResponse(p)
 	case *packet.PlayerListItem:
 		b.handlePlayerListItem(p, pc)
+	case *packet.UpsertPlayerInfo:
+		b.handleUpsertPlayerInfo(p, pc)
+	case *packet.RemovePlayerInfo:
+		b.handleRemovePlayerInfo(p, pc)
 	case *packet.ResourcePackRequest:
 		b.handleResourcePacketRequest(p)
 	case *packet.ServerData:
 		b.handleServerData(p)
 	case *bossbar.BossBar:
 		b.handleBossBar(p, pc)
+	case *packet.HeaderAndFooter:
+		b.handleHeaderAndFooter(p, pc)
 	default:
 		b.forwardToPlayer(pc, nil)
 	}
@@ -88,7 +102,7 @@ func (b *backendPlaySessionHandler) shouldHandle() bool {
 
 func (b *backendPlaySessionHandler) Activated() {
 	b.serverConn.server.players.add(b.serverConn.player)
-	if b.proxy().cfg.BungeePluginChannelEnabled {
+	if b.proxy().config().BungeePluginChannelEnabled {
 		serverMc, ok := b.serverConn.ensureConnected()
 		if ok {
 			protocol := serverMc.Protocol()
@@ -96,6 +110,7 @@ func (b *backendPlaySessionHandler) Activated() {
 			_ = serverMc.WritePacket(channelsPacket)
 		}
 	}
+	b.proxy().initHeaderFooter(b.serverConn)
 }
 
 func (b *backendPlaySessionHandler) Disconnected() {
@@ -198,7 +213,7 @@ func (b *backendPlaySessionHandler) handlePluginMessage(packet *plugin.Message,
 }
 
 func (b *backendPlaySessionHandler) handleServerData(p *packet.ServerData) {
-	ping := newInitialPing(b.proxy(), b.serverConn.player.Protocol())
+	ping := newInitialPing(b.proxy(), b.serverConn.player.VirtualHost(), b.serverConn.player.Protocol())
 	e := &PingEvent{
 		inbound: b.serverConn.player,
 		ping:    ping,
@@ -266,9 +281,25 @@ func (b *backendPlaySessionHandler) handlePlayerListItem(p *packet.PlayerListIte
 	b.forwardToPlayer(pc, nil)
 }
 
+func (b *backendPlaySessionHandler) handleUpsertPlayerInfo(p *packet.UpsertPlayerInfo, pc *proto.PacketContext) {
+	// Track changes to tab list of player
+	if err := b.serverConn.player.tabList.ProcessUpsert(p); err != nil {
+		b.serverConn.log.Error(err, "Error while processing backend UpsertPlayerInfo packet, ignored")
+	}
+	b.forwardToPlayer(pc, nil)
+}
+
+func (b *backendPlaySessionHandler) handleRemovePlayerInfo(p *packet.RemovePlayerInfo, pc *proto.PacketContext) {
+	// Track changes to tab list of player
+	if err := b.serverConn.player.tabList.ProcessRemove(p); err != nil {
+		b.serverConn.log.Error(err, "Error while processing backend RemovePlayerInfo packet, ignored")
+	}
+	b.forwardToPlayer(pc, nil)
+}
+
 func (b *backendPlaySessionHandler) handleAvailableCommands(p *packet.AvailableCommands) {
 	rootNode := p.RootNode
-	if b.proxy().cfg.AnnounceProxyCommands {
+	if b.proxy().config().AnnounceProxyCommands {
 		// Inject commands from the proxy.
 		dispatcherRootNode := filterNode(&b.proxy().command.Root, b.serverConn.player)
 		if dispatcherRootNode == nil {
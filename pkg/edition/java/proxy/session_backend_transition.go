@@ -20,6 +20,7 @@ type backendTransitionSessionHandler struct {
 
 	serverConn                *serverConnection
 	requestCtx                *connRequestCxt
+	previousServer            RegisteredServer // nil-able, set if the player left it for the config state
 	bungeeCordMessageRecorder bungeecord.MessageResponder
 	listenDoneCtx             chan struct{}
 	log                       logr.Logger
@@ -32,7 +33,7 @@ func newBackendTransitionSessionHandler(
 	requestCtx *connRequestCxt,
 	eventMgr event.Manager,
 	proxy *Proxy,
-) netmc.SessionHandler {
+) *backendTransitionSessionHandler {
 	return &backendTransitionSessionHandler{
 		eventMgr:   eventMgr,
 		serverConn: serverConn,
@@ -185,7 +186,7 @@ func (b *backendTransitionSessionHandler) handleJoinGame(pc *proto.PacketContext
 
 	b.serverConn.player.mu.Lock()
 	existingConn := b.serverConn.player.connectedServer_
-	var previousServer RegisteredServer
+	previousServer := b.previousServer
 	if existingConn != nil {
 		previousServer = existingConn.server
 		// Shut down the existing server connection.
@@ -0,0 +1,144 @@
+package proxy
+
+import (
+	"sync"
+
+	"github.com/gammazero/deque"
+	"github.com/go-logr/logr"
+
+	"go.minekube.com/gate/pkg/edition/java/netmc"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/plugin"
+	"go.minekube.com/gate/pkg/edition/java/proto/state"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// Handles communication with a 1.20.2+ client in the config state.
+// The config state is entered after login and when switching servers.
+type clientConfigSessionHandler struct {
+	player *connectedPlayer
+	log    logr.Logger
+
+	finishedOnce sync.Once
+	finished     chan struct{} // closed when the client acknowledged the finished update
+
+	mu                   sync.Mutex
+	queuedPluginMessages deque.Deque[*plugin.Message] // sent before a backend entered the config state
+
+	nopSessionHandler
+}
+
+var _ netmc.SessionHandler = (*clientConfigSessionHandler)(nil)
+
+func newClientConfigSessionHandler(player *connectedPlayer) *clientConfigSessionHandler {
+	return &clientConfigSessionHandler{
+		player:   player,
+		log:      player.log.WithName("clientConfigSession"),
+		finished: make(chan struct{}),
+	}
+}
+
+func (h *clientConfigSessionHandler) HandlePacket(pc *proto.PacketContext) {
+	if !pc.KnownPacket {
+		h.forwardToServer(pc)
+		return
+	}
+
+	switch p := pc.Packet.(type) {
+	case *packet.KeepAlive:
+		// The proxy sends no keep alive packets to clients in the config state
+		// and keep alive packets of the backend server are answered by the proxy.
+	case *packet.ClientSettings:
+		h.player.setSettings(p)
+		h.forwardToServer(pc)
+	case *plugin.Message:
+		h.handlePluginMessage(p)
+	case *config.FinishedUpdate:
+		h.handleFinishedUpdate()
+	default:
+		h.forwardToServer(pc)
+	}
+}
+
+func (h *clientConfigSessionHandler) Disconnected() {
+	h.player.teardown()
+}
+
+func (h *clientConfigSessionHandler) PlayerLog() logr.Logger {
+	return h.player.log
+}
+
+// backendConn returns the connection of the backend server that is in the
+// config state with the player or nil if there is none.
+func (h *clientConfigSessionHandler) backendConn() netmc.MinecraftConn {
+	serverConn := h.player.connectionInFlight()
+	if serverConn == nil {
+		serverConn = h.player.connectedServer()
+	}
+	serverMc, ok := serverConn.ensureConnected()
+	if !ok || serverMc.State() != state.Config {
+		return nil
+	}
+	return serverMc
+}
+
+func (h *clientConfigSessionHandler) forwardToServer(pc *proto.PacketContext) {
+	if serverMc := h.backendConn(); serverMc != nil {
+		_ = serverMc.Write(pc.Payload)
+	}
+}
+
+func (h *clientConfigSessionHandler) handlePluginMessage(p *plugin.Message) {
+	if plugin.IsRegister(p) {
+		h.player.pluginChannelsMu.Lock()
+		h.player.pluginChannels.Insert(plugin.Channels(p)...)
+		h.player.pluginChannelsMu.Unlock()
+	} else if plugin.IsUnregister(p) {
+		h.player.pluginChannelsMu.Lock()
+		h.player.pluginChannels.Delete(plugin.Channels(p)...)
+		h.player.pluginChannelsMu.Unlock()
+	} else if plugin.McBrand(p) {
+		h.player.handleClientBrand(p)
+		p = plugin.RewriteMinecraftBrand(p, h.player.Protocol())
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if serverMc := h.backendConn(); serverMc != nil {
+		_ = serverMc.WritePacket(p)
+		return
+	}
+	// No backend server is ready yet, send the message
+	// once a backend server entered the config state.
+	h.queuedPluginMessages.PushBack(p)
+}
+
+// flushQueuedPluginMessages sends the plugin messages the client sent
+// before the backend server entered the config state.
+func (h *clientConfigSessionHandler) flushQueuedPluginMessages(serverMc netmc.MinecraftConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for h.queuedPluginMessages.Len() != 0 {
+		_ = serverMc.BufferPacket(h.queuedPluginMessages.PopFront())
+	}
+	_ = serverMc.Flush()
+}
+
+// handleBackendFinishUpdate tells the client that the backend server finished
+// the configuration and returns a channel that is closed when the client
+// acknowledged it and switched to the play state.
+func (h *clientConfigSessionHandler) handleBackendFinishUpdate() (<-chan struct{}, error) {
+	if err := h.player.WritePacket(&config.FinishedUpdate{}); err != nil {
+		return nil, err
+	}
+	return h.finished, nil
+}
+
+func (h *clientConfigSessionHandler) handleFinishedUpdate() {
+	h.finishedOnce.Do(func() {
+		h.player.SetState(state.Play)
+		h.player.SetSessionHandler(newClientPlaySessionHandler(h.player))
+		close(h.finished)
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf16"
 
 	"github.com/gammazero/deque"
 	"go.minekube.com/brigodier"
@@ -14,6 +16,7 @@ import (
 	"go.minekube.com/common/minecraft/component"
 	"go.minekube.com/gate/pkg/edition/java/netmc"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet/bossbar"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet/config"
 	"go.minekube.com/gate/pkg/edition/java/proxy/crypto"
 	"go.minekube.com/gate/pkg/edition/java/proxy/message"
 	"go.minekube.com/gate/pkg/edition/java/proxy/phase"
@@ -46,6 +49,9 @@ type clientPlaySessionHandler struct {
 
 	serverBossBars         map[uuid.UUID]struct{}
 	outstandingTabComplete *packet.TabCompleteRequest
+
+	mu           sync.Mutex
+	configSwitch chan struct{} // 1.20.2+, closed when the player switched to the config state
 }
 
 func newClientPlaySessionHandler(player *connectedPlayer) *clientPlaySessionHandler {
@@ -61,6 +67,9 @@ func newClientPlaySessionHandler(player *connectedPlayer) *clientPlaySessionHand
 var _ netmc.SessionHandler = (*clientPlaySessionHandler)(nil)
 
 func (c *clientPlaySessionHandler) HandlePacket(pc *proto.PacketContext) {
+	if !c.proxy().interceptPacket(c.player.MinecraftConn, pc, c.player, c.player.connectedServer()) {
+		return
+	}
 	if !pc.KnownPacket {
 		c.forwardToServer(pc)
 		return
@@ -75,6 +84,10 @@ func (c *clientPlaySessionHandler) HandlePacket(pc *proto.PacketContext) {
 		c.handlePlayerChat(p)
 	case *packet.PlayerCommand:
 		c.handlePlayerCommand(p)
+	case *packet.SessionPlayerChat:
+		c.handleSessionPlayerChat(p)
+	case *packet.SessionPlayerCommand:
+		c.handleSessionPlayerCommand(p)
 	case *packet.TabCompleteRequest:
 		c.handleTabCompleteRequest(p)
 	case *plugin.Message:
@@ -82,6 +95,8 @@ func (c *clientPlaySessionHandler) HandlePacket(pc *proto.PacketContext) {
 	case *packet.ClientSettings:
 		c.player.setSettings(p)
 		c.forwardToServer(pc) // forward to server
+	case *config.FinishedUpdate:
+		c.handleFinishedUpdate(pc)
 	default:
 		c.forwardToServer(pc)
 	}
@@ -143,6 +158,67 @@ func (c *clientPlaySessionHandler) FlushQueuedPluginMessages() {
 	_ = serverMc.Flush()
 }
 
+// switchToConfig switches a 1.20.2+ player from the play state to the config state
+// to join a new server. It returns the server the player left and a channel that
+// is closed when the player acknowledged the switch.
+func (c *clientPlaySessionHandler) switchToConfig() (previousServer RegisteredServer, done <-chan struct{}, err error) {
+	c.player.mu.Lock()
+	existingConn := c.player.connectedServer_
+	c.player.connectedServer_ = nil
+	c.player.mu.Unlock()
+	if existingConn != nil {
+		previousServer = existingConn.server
+		// Shut down the existing server connection.
+		existingConn.disconnect()
+		// Send keep alive to try to avoid timeouts
+		if err = netmc.SendKeepAlive(c.player); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The config state resets the client, so there is nothing to clean up later.
+	c.spawned.Store(false)
+	c.serverBossBars = make(map[uuid.UUID]struct{})
+	_ = tablist.BufferClearTabListEntries(c.player.tabList, func(proto.Packet) error { return nil })
+
+	switched := make(chan struct{})
+	c.mu.Lock()
+	c.configSwitch = switched
+	c.mu.Unlock()
+	if err = c.player.WritePacket(&config.StartUpdate{}); err != nil {
+		return nil, nil, err
+	}
+	return previousServer, switched, nil
+}
+
+// handleFinishedUpdate handles the player acknowledging a switch to the config state
+// that was either started by the proxy or by the connected server.
+func (c *clientPlaySessionHandler) handleFinishedUpdate(pc *proto.PacketContext) {
+	c.mu.Lock()
+	configSwitch := c.configSwitch
+	c.configSwitch = nil
+	c.mu.Unlock()
+
+	c.player.SetState(state.Config)
+	c.player.SetSessionHandler(newClientConfigSessionHandler(c.player))
+
+	if configSwitch != nil {
+		// The proxy switches the player to a new server.
+		close(configSwitch)
+		return
+	}
+
+	// The connected server reconfigures the player.
+	serverConn := c.player.connectedServer()
+	serverMc, ok := serverConn.ensureConnected()
+	if !ok {
+		return
+	}
+	serverMc.SetState(state.Config)
+	serverMc.SetSessionHandler(newBackendConfigSessionHandler(serverConn, nil))
+	_ = serverMc.Write(pc.Payload)
+}
+
 type (
 	backendConnAdapter struct{ netmc.MinecraftConn }
 	keepAliveAdapter   struct{ *connectedPlayer }
@@ -223,7 +299,7 @@ func (c *clientPlaySessionHandler) handlePluginMessage(packet *plugin.Message) {
 			})
 		}
 	} else if plugin.McBrand(packet) {
-		// TODO read brand message & fire PlayerClientBrandEvent & cache client brand
+		c.player.handleClientBrand(packet)
 		_ = backendConn.WritePacket(plugin.RewriteMinecraftBrand(packet, c.player.Protocol()))
 	} else {
 		serverConnPhase := serverConn.phase()
@@ -468,6 +544,7 @@ func respawnFromJoinGame(joinGame *packet.JoinGame) *packet.Respawn {
 		PreviousGamemode:     joinGame.PreviousGamemode,
 		CurrentDimensionData: joinGame.CurrentDimensionData,
 		LastDeathPosition:    joinGame.LastDeadPosition,
+		PortalCooldown:       joinGame.PortalCooldown,
 	}
 }
 
@@ -622,6 +699,146 @@ func (c *clientPlaySessionHandler) processPlayerChat(msg string, signedChatMessa
 	})
 }
 
+// Handles chat messages of 1.19.3+ clients.
+// Signed messages can't be modified or denied without breaking the player's chat session.
+func (c *clientPlaySessionHandler) handleSessionPlayerChat(p *packet.SessionPlayerChat) {
+	serverConn := c.player.connectedServer()
+	if _, ok := serverConn.ensureConnected(); !ok {
+		return
+	}
+	if !c.validateChat(p.Message) {
+		return
+	}
+	e := &PlayerChatEvent{
+		player:   c.player,
+		original: p.Message,
+	}
+	event.FireParallel(c.proxy().Event(), e, func(e *PlayerChatEvent) {
+		if !c.player.Active() {
+			return
+		}
+		serverMc, ok := serverConn.ensureConnected()
+		if !ok {
+			return
+		}
+		if !e.Allowed() {
+			if p.Signed {
+				c.log.Info("a plugin denied a signed chat message, disconnecting player")
+				c.player.Disconnect(illegalProtocolState)
+				return
+			}
+			_ = acknowledgeChat(serverMc, p.LastSeenMessages)
+			return
+		}
+		if e.modified != "" {
+			c.log1.Info("player sent chat message",
+				"original", e.Original(), "modified", e.modified)
+			if p.Signed {
+				c.log.Info("a plugin changed a signed chat message, disconnecting player")
+				c.player.Disconnect(illegalProtocolState)
+				return
+			}
+			write := packet.NewChatBuilder(c.player.Protocol()).
+				Message(e.Message()).
+				Time(p.Timestamp).
+				LastSeenMessages(p.LastSeenMessages).
+				ToServer()
+			_ = serverMc.WritePacket(write)
+			return
+		}
+		c.log1.Info("player sent chat message", "chat", e.Message())
+		_ = serverMc.WritePacket(p)
+	})
+}
+
+// Handles commands of 1.19.3+ clients.
+func (c *clientPlaySessionHandler) handleSessionPlayerCommand(p *packet.SessionPlayerCommand) {
+	if !c.validateChat(p.Command) {
+		return
+	}
+	if _, ok := c.player.ensureBackendConnection(); !ok {
+		return
+	}
+	e := &CommandExecuteEvent{
+		source:          c.player,
+		commandline:     p.Command,
+		originalCommand: p.Command,
+	}
+	event.FireParallel(c.proxy().Event(), e, func(e *CommandExecuteEvent) {
+		err := c.processSessionCommandExecuteResult(e, p)
+		if err != nil {
+			c.log.Error(err, "error while running command", "command", p.Command)
+			_ = c.player.SendMessage(&component.Text{
+				Content: "An error occurred while running this command.",
+				S:       component.Style{Color: color.Red},
+			})
+		}
+	})
+}
+
+func (c *clientPlaySessionHandler) processSessionCommandExecuteResult(result *CommandExecuteEvent, p *packet.SessionPlayerCommand) error {
+	if !c.player.Active() {
+		return nil
+	}
+	smc, ok := c.player.ensureBackendConnection()
+	if !ok {
+		c.player.Disconnect(internalServerConnectionError)
+		return nil
+	}
+
+	if !result.Allowed() {
+		if p.Signed() {
+			c.log.Info("a plugin denied a signed command, disconnecting player")
+			c.player.Disconnect(illegalProtocolState)
+			return nil
+		}
+		return acknowledgeChat(smc, p.LastSeenMessages)
+	}
+
+	c.logCommandExecution(result)
+
+	forwardToServer := func() error {
+		if result.Command() == p.Command {
+			return smc.WritePacket(p)
+		}
+		if p.Signed() {
+			c.log.Info("a plugin changed a signed command, disconnecting player")
+			c.player.Disconnect(illegalProtocolState)
+			return nil
+		}
+		write := packet.NewChatBuilder(c.player.Protocol()).
+			Message("/" + result.Command()).
+			Time(p.Timestamp).
+			LastSeenMessages(p.LastSeenMessages).
+			ToServer()
+		return smc.WritePacket(write)
+	}
+
+	if result.Forward() {
+		return forwardToServer()
+	}
+
+	// Exec command
+	hasRun, err := c.executeCommand(result.Command())
+	if err != nil {
+		return err
+	}
+	if !hasRun {
+		return forwardToServer()
+	}
+	// The server must still learn about the messages the player has seen.
+	return acknowledgeChat(smc, p.LastSeenMessages)
+}
+
+// acknowledgeChat tells the server about the messages seen by a 1.19.3+ client
+// when the proxy didn't forward the client's chat message or command.
+func acknowledgeChat(serverMc netmc.MinecraftConn, lastSeen packet.LastSeenMessages) error {
+	if lastSeen.Offset == 0 {
+		return nil
+	}
+	return serverMc.WritePacket(&packet.ChatAcknowledgement{Offset: lastSeen.Offset})
+}
+
 func (c *clientPlaySessionHandler) validateChat(msg string) bool {
 	if validation.ContainsIllegalCharacter(msg) {
 		c.player.Disconnect(illegalChatCharacters)
@@ -641,15 +858,7 @@ func (c *clientPlaySessionHandler) processCommandExecuteResult(result *CommandEx
 		return nil
 	}
 
-	// Log player executed command
-	log := c.log
-	if result.Command() == result.OriginalCommand() {
-		log = log.WithValues("command", result.Command())
-	} else {
-		log = log.WithValues("original", result.OriginalCommand(),
-			"changed", result.Command())
-	}
-	log.Info("player executing command")
+	c.logCommandExecution(result)
 
 	forwardToServer := func() error {
 		write := packet.NewChatBuilder(c.player.Protocol()).AsPlayer(c.player.ID())
@@ -678,6 +887,18 @@ func (c *clientPlaySessionHandler) processCommandExecuteResult(result *CommandEx
 	return nil
 }
 
+// Logs the player executed command.
+func (c *clientPlaySessionHandler) logCommandExecution(result *CommandExecuteEvent) {
+	log := c.log
+	if result.Command() == result.OriginalCommand() {
+		log = log.WithValues("command", result.Command())
+	} else {
+		log = log.WithValues("original", result.OriginalCommand(),
+			"changed", result.Command())
+	}
+	log.Info("player executing command")
+}
+
 func (c *clientPlaySessionHandler) executeCommand(cmd string) (hasRun bool, err error) {
 	// Make invoke context
 	ctx, cancel := context.WithCancel(c.player.Context())
@@ -736,36 +957,58 @@ func (c *clientPlaySessionHandler) handleCommandTabComplete(p *packet.TabComplet
 
 	ctx, cancel := context.WithCancel(c.player.Context())
 	defer cancel()
-	suggestions, err := c.proxy().command.OfferSuggestions(ctx, c.player, cmd)
+	mgr := c.proxy().command
+	suggestions, err := mgr.CompletionSuggestions(mgr.Parse(ctx, c.player, cmd))
 	if err != nil {
 		c.log.Error(err, "Error while handling command tab completion for player",
 			"command", cmd)
 		return
 	}
-	if len(suggestions) == 0 {
+
+	offers := make([]packet.TabCompleteOffer, 0, len(suggestions.Suggestions))
+	for _, suggestion := range suggestions.Suggestions {
+		offers = append(offers, packet.TabCompleteOffer{
+			Text:    suggestion.Text,
+			Tooltip: command.TooltipComponent(suggestion.Tooltip),
+		})
+	}
+	offers = c.fireTabCompleteEvent(p.Command, offers)
+	if len(offers) == 0 {
 		return
 	}
 	if c.log1.Enabled() {
-		c.log1.Info("Response to TabCompleteRequest", "cmd", cmd, "suggestions", suggestions)
+		c.log1.Info("Response to TabCompleteRequest", "cmd", cmd, "offers", len(offers))
 	}
 
-	offers := make([]packet.TabCompleteOffer, 0, len(suggestions))
-	for _, suggestion := range suggestions {
-		offers = append(offers, packet.TabCompleteOffer{
-			Text: suggestion,
-			// TODO support brigadier tooltip
-		})
-	}
+	start, length := tabCompleteRange(p.Command, cmd, startPos, suggestions)
 
 	// Send suggestions
 	_ = c.player.WritePacket(&packet.TabCompleteResponse{
 		TransactionID: p.TransactionID,
-		Start:         startPos,
-		Length:        len(p.Command) - startPos,
+		Start:         start,
+		Length:        length,
 		Offers:        offers,
 	})
 }
 
+// tabCompleteRange returns the range of the command the suggestions replace as start and length
+// in UTF-16 code units as expected by the client. The suggestions range refers to cmd, the command
+// without the leading slash, and startPos is the byte index after the last space in the command.
+func tabCompleteRange(command, cmd string, startPos int, suggestions *brigodier.Suggestions) (start, length int) {
+	end := len(command)
+	start = startPos
+	if len(suggestions.Suggestions) != 0 {
+		offset := len(command) - len(cmd)
+		start, end = offset+suggestions.Range.Start, offset+suggestions.Range.End
+	}
+	return utf16Len(command[:start]), utf16Len(command[start:end])
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
 func (c *clientPlaySessionHandler) handleRegularTabComplete(p *packet.TabCompleteRequest) {
 	if c.player.Protocol().Lower(version.Minecraft_1_13) {
 		// Outstanding tab completes are recorded for use with 1.12 clients and below to provide
@@ -810,6 +1053,7 @@ func (c *clientPlaySessionHandler) finishCommandTabComplete(request *packet.TabC
 			Text: offer,
 		})
 	}
+	response.Offers = c.fireTabCompleteEvent(request.Command, response.Offers)
 	// Sort offers alphabetically
 	sort.Slice(response.Offers, func(i, j int) bool {
 		return response.Offers[i].Text < response.Offers[j].Text
@@ -822,20 +1066,32 @@ func (c *clientPlaySessionHandler) PlayerLog() logr.Logger {
 }
 
 func (c *clientPlaySessionHandler) finishRegularTabComplete(request *packet.TabCompleteRequest, response *packet.TabCompleteResponse) {
-	offers := make([]string, 0, len(response.Offers))
-	for _, offer := range response.Offers {
-		offers = append(offers, offer.Text)
-	}
+	response.Offers = c.fireTabCompleteEvent(request.Command, response.Offers)
+	_ = c.player.WritePacket(response)
+}
 
+// fireTabCompleteEvent fires a TabCompleteEvent for the offers
+// and returns the offers as modified by the event handlers.
+func (c *clientPlaySessionHandler) fireTabCompleteEvent(partialMessage string, offers []packet.TabCompleteOffer) []packet.TabCompleteOffer {
 	e := &TabCompleteEvent{
 		player:         c.player,
-		partialMessage: request.Command,
-		suggestions:    offers,
+		partialMessage: partialMessage,
+		suggestions:    make([]string, 0, len(offers)),
+		tooltips:       map[string]component.Component{},
+	}
+	for _, offer := range offers {
+		e.suggestions = append(e.suggestions, offer.Text)
+		if offer.Tooltip != nil {
+			e.tooltips[offer.Text] = offer.Tooltip
+		}
 	}
 	c.proxy().event.Fire(e)
-	response.Offers = nil
+	offers = make([]packet.TabCompleteOffer, 0, len(e.suggestions))
 	for _, suggestion := range e.suggestions {
-		response.Offers = append(response.Offers, packet.TabCompleteOffer{Text: suggestion})
+		offers = append(offers, packet.TabCompleteOffer{
+			Text:    suggestion,
+			Tooltip: e.tooltips[suggestion],
+		})
 	}
-	_ = c.player.WritePacket(response)
+	return offers
 }
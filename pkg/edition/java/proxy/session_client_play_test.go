@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+func TestTabCompleteRange(t *testing.T) {
+	suggest := func(start, end int) *brigodier.Suggestions {
+		return &brigodier.Suggestions{
+			Range:       brigodier.StringRange{Start: start, End: end},
+			Suggestions: []*brigodier.Suggestion{{Text: "x"}},
+		}
+	}
+	tests := []struct {
+		name          string
+		command       string
+		suggestions   *brigodier.Suggestions
+		start, length int
+	}{
+		{
+			name:        "no suggestions",
+			command:     "/server lo",
+			suggestions: &brigodier.Suggestions{},
+			start:       8, length: 2,
+		},
+		{
+			name:        "ascii",
+			command:     "/server lo",
+			suggestions: suggest(7, 9),
+			start:       8, length: 2,
+		},
+		{
+			name:        "no suggestions non-ascii",
+			command:     "/msg ä bö",
+			suggestions: &brigodier.Suggestions{},
+			start:       7, length: 2,
+		},
+		{
+			name:        "non-ascii",
+			command:     "/msg ä bö",
+			suggestions: suggest(4, 6), // "ä"
+			start:       5, length: 1,
+		},
+		{
+			name:        "surrogate pair",
+			command:     "/say 😀 x",
+			suggestions: suggest(4, 8), // "😀"
+			start:       5, length: 2,
+		},
+		{
+			name:        "after surrogate pair",
+			command:     "/say 😀 x",
+			suggestions: suggest(9, 10), // "x"
+			start:       8, length: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := strings.TrimPrefix(tt.command, "/")
+			startPos := strings.LastIndex(tt.command, " ") + 1
+			start, length := tabCompleteRange(tt.command, cmd, startPos, tt.suggestions)
+			require.Equal(t, tt.start, start)
+			require.Equal(t, tt.length, length)
+		})
+	}
+}
+
+func TestClientPlaySessionHandler_fireTabCompleteEvent(t *testing.T) {
+	p := newTestProxy(t, config.DefaultConfig)
+	c := &clientPlaySessionHandler{player: &connectedPlayer{
+		sessionHandlerDeps: &sessionHandlerDeps{proxy: p, configProvider: p},
+	}}
+	var partialMessage string
+	var tooltip component.Component
+	p.event.Subscribe(&TabCompleteEvent{}, 0, func(e event.Event) {
+		tc := e.(*TabCompleteEvent)
+		partialMessage, tooltip = tc.PartialMessage(), tc.Tooltip("ärger")
+		tc.SetSuggestions(append(tc.Suggestions(), "öl"))
+		tc.SetTooltip("öl", &component.Text{Content: "Öl"})
+	})
+
+	offers := c.fireTabCompleteEvent("/msg ä", []packet.TabCompleteOffer{
+		{Text: "ärger", Tooltip: &component.Text{Content: "Ärger"}},
+		{Text: "äpfel"},
+	})
+	require.Equal(t, []packet.TabCompleteOffer{
+		{Text: "ärger", Tooltip: &component.Text{Content: "Ärger"}},
+		{Text: "äpfel"},
+		{Text: "öl", Tooltip: &component.Text{Content: "Öl"}},
+	}, offers)
+	require.Equal(t, "/msg ä", partialMessage)
+	require.Equal(t, &component.Text{Content: "Ärger"}, tooltip)
+}
@@ -68,7 +68,8 @@ func (h *handshakeSessionHandler) HandlePacket(p *proto.PacketContext) {
 		return
 	}
 	switch typed := p.Packet.(type) {
-	// TODO legacy pings
+	case *packet.LegacyPing:
+		h.handleLegacyPing(typed)
 	case *packet.Handshake:
 		h.handleHandshake(typed)
 	default:
@@ -78,6 +79,19 @@ func (h *handshakeSessionHandler) HandlePacket(p *proto.PacketContext) {
 	}
 }
 
+func (h *handshakeSessionHandler) handleLegacyPing(p *packet.LegacyPing) {
+	vHost := p.VirtualHost
+	if vHost == nil {
+		// Clients older than 1.6 don't send the address they connected to
+		vHost = h.conn.LocalAddr()
+	}
+	inbound := newInitialInbound(h.conn, vHost)
+
+	handler := newStatusSessionHandler(h.conn, inbound, h.sessionHandlerDeps)
+	h.conn.SetSessionHandler(handler)
+	handler.handleLegacyPing(p)
+}
+
 func (h *handshakeSessionHandler) handleHandshake(handshake *packet.Handshake) {
 	vHost := netutil.NewAddr(
 		fmt.Sprintf("%s:%d", handshake.ServerAddress, handshake.Port),
@@ -120,6 +134,7 @@ func (h *handshakeSessionHandler) handleLogin(p *packet.Handshake, inbound *init
 
 	// Client IP-block rate limiter preventing too fast logins hitting the Mojang API
 	if h.loginsQuota != nil && h.loginsQuota.Blocked(netutil.Host(inbound.RemoteAddr())) {
+		quotaRejections.WithLabelValues(quotaLogins).Inc()
 		_ = netmc.CloseWith(h.conn, packet.DisconnectWith(&component.Text{
 			Content: "You are logging in too fast, please calm down and retry.",
 			S:       component.Style{Color: color.Red},
@@ -101,6 +101,7 @@ func (l *initialLoginSessionHandler) handleServerLogin(login *packet.ServerLogin
 	if playerKey != nil {
 		if playerKey.Expired() {
 			l.log.V(1).Info("expired player public key")
+			loginFailed(loginInvalidKey)
 			_ = l.inbound.disconnect(&component.Translation{
 				Key: "multiplayer.disconnect.invalid_public_key_signature",
 			})
@@ -116,13 +117,17 @@ func (l *initialLoginSessionHandler) handleServerLogin(login *packet.ServerLogin
 
 		if !isKeyValid {
 			l.log.V(1).Info("invalid player public key signature")
+			loginFailed(loginInvalidKey)
 			_ = l.inbound.disconnect(&component.Translation{
 				Key: "multiplayer.disconnect.invalid_public_key",
 			})
 			return
 		}
 	} else if l.conn.Protocol().GreaterEqual(version.Minecraft_1_19) &&
+		// 1.19.3+ clients don't send a key on login anymore
+		l.conn.Protocol().Lower(version.Minecraft_1_19_3) &&
 		l.config().ForceKeyAuthentication {
+		loginFailed(loginInvalidKey)
 		_ = l.inbound.disconnect(&component.Translation{
 			Key: "multiplayer.disconnect.missing_public_key",
 		})
@@ -133,6 +138,7 @@ func (l *initialLoginSessionHandler) handleServerLogin(login *packet.ServerLogin
 
 	// Validate username format
 	if !playerNameRegex.MatchString(login.Username) {
+		loginFailed(loginInvalidName)
 		_ = l.inbound.disconnect(invalidPlayerName)
 		return
 	}
@@ -145,6 +151,7 @@ func (l *initialLoginSessionHandler) handleServerLogin(login *packet.ServerLogin
 	}
 
 	if e.Result() == DeniedPreLogin {
+		loginFailed(loginPreLoginDenied)
 		_ = l.inbound.disconnect(e.Reason())
 		return
 	}
@@ -258,6 +265,7 @@ func (l *initialLoginSessionHandler) handleEncryptionResponse(resp *packet.Encry
 	authn := l.auth()
 	decryptedSharedSecret, err := authn.DecryptSharedSecret(resp.SharedSecret)
 	if err != nil {
+		loginFailed(loginAuthFailed)
 		// Simple close the connection without much overhead.
 		_ = l.conn.Close()
 		return
@@ -267,6 +275,7 @@ func (l *initialLoginSessionHandler) handleEncryptionResponse(resp *packet.Encry
 	// Once the client sends EncryptionResponse, encryption is enabled.
 	if err = l.conn.EnableEncryption(decryptedSharedSecret); err != nil {
 		l.log.Error(err, "error enabling encryption for connecting player")
+		loginFailed(loginInternalError)
 		_ = netmc.CloseWith(l.conn, packet.DisconnectWith(internalServerConnectionError))
 		return
 	}
@@ -278,6 +287,7 @@ func (l *initialLoginSessionHandler) handleEncryptionResponse(resp *packet.Encry
 
 	serverID, err := authn.GenerateServerID(decryptedSharedSecret)
 	if err != nil {
+		loginFailed(loginInternalError)
 		// Simple close the connection without much overhead.
 		_ = l.inbound.disconnect(unableAuthWithMojang)
 		return
@@ -293,12 +303,14 @@ func (l *initialLoginSessionHandler) handleEncryptionResponse(resp *packet.Encry
 			// The player disconnected before receiving we could authenticate.
 			return
 		}
+		loginFailed(loginAuthFailed)
 		_ = netmc.CloseWith(l.conn, packet.DisconnectWith(unableAuthWithMojang))
 		return
 	}
 
 	if !authResp.OnlineMode() {
 		log.Info("disconnect offline mode player")
+		loginFailed(loginOfflineMode)
 		// Apparently an offline-mode user logged onto this online-mode proxy.
 		_ = netmc.CloseWith(l.conn, packet.DisconnectWith(onlineModeOnly))
 		return
@@ -307,6 +319,7 @@ func (l *initialLoginSessionHandler) handleEncryptionResponse(resp *packet.Encry
 	// Extract game profile from response.
 	gameProfile, err := authResp.GameProfile()
 	if err != nil {
+		loginFailed(loginAuthFailed)
 		if netmc.CloseWith(l.conn, packet.DisconnectWith(unableAuthWithMojang)) == nil {
 			log.Error(err, "unable get GameProfile from Mojang authentication response")
 		}
@@ -353,6 +366,10 @@ var (
 		Content: "Illegal characters in chat",
 		S:       component.Style{Color: color.Red},
 	}
+	illegalProtocolState = &component.Text{
+		Content: "A proxy plugin caused an illegal protocol state. Contact your network administrator.",
+		S:       component.Style{Color: color.Red},
+	}
 )
 
 func (l *initialLoginSessionHandler) Disconnected() {
@@ -3,6 +3,7 @@ package proxy
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 
 	"github.com/go-logr/logr"
 	"go.minekube.com/gate/pkg/edition/java/netmc"
@@ -28,7 +29,7 @@ func newStatusSessionHandler(
 	conn netmc.MinecraftConn,
 	inbound Inbound,
 	sessionHandlerDeps *sessionHandlerDeps,
-) netmc.SessionHandler {
+) *statusSessionHandler {
 	return &statusSessionHandler{
 		sessionHandlerDeps: sessionHandlerDeps,
 		conn:               conn,
@@ -70,7 +71,7 @@ func (h *statusSessionHandler) HandlePacket(pc *proto.PacketContext) {
 
 var versionName = fmt.Sprintf("Gate %s", version.SupportedVersionsString)
 
-func newInitialPing(p *Proxy, protocol proto.Protocol) *ping.ServerPing {
+func newInitialPing(p *Proxy, virtualHost net.Addr, protocol proto.Protocol) *ping.ServerPing {
 	shownVersion := protocol
 	if !version.Protocol(protocol).Supported() {
 		shownVersion = version.MaximumVersion.Protocol
@@ -82,37 +83,47 @@ func newInitialPing(p *Proxy, protocol proto.Protocol) *ping.ServerPing {
 		},
 		Players: &ping.Players{
 			Online: p.PlayerCount(),
-			Max:    p.cfg.Status.ShowMaxPlayers,
+			Max:    p.config().Status.ShowMaxPlayers,
 		},
-		Description: p.motd,
-		Favicon:     p.favicon,
+		Description: p.motdFor(virtualHost),
+		Favicon:     p.statusFavicon(),
 	}
 }
 
-func (h *statusSessionHandler) handleStatusRequest() {
+// firePing fires the PingEvent and returns the resulting ping or
+// nil if no response should be sent, in which case the connection is closed.
+func (h *statusSessionHandler) firePing(protocol proto.Protocol) *ping.ServerPing {
 	if h.receivedRequest {
 		// Already sent response
 		_ = h.conn.Close()
-		return
+		return nil
 	}
 	h.receivedRequest = true
 
 	e := &PingEvent{
 		inbound: h.inbound,
-		ping:    newInitialPing(h.proxy, h.conn.Protocol()),
+		ping:    h.proxy.passthroughPing(h.inbound, protocol, newInitialPing(h.proxy, h.inbound.VirtualHost(), protocol)),
 	}
 	h.eventMgr.Fire(e)
 
 	if e.ping == nil {
 		_ = h.conn.Close()
 		h.log.V(1).Info("ping response was set to nil by an event handler, no response is sent")
-		return
+		return nil
 	}
 	if !h.inbound.Active() {
+		return nil
+	}
+	return e.ping
+}
+
+func (h *statusSessionHandler) handleStatusRequest() {
+	pong := h.firePing(h.conn.Protocol())
+	if pong == nil {
 		return
 	}
 
-	response, err := json.Marshal(e.ping)
+	response, err := json.Marshal(pong)
 	if err != nil {
 		_ = h.conn.Close()
 		h.log.Error(err, "error marshaling ping response to json")
@@ -123,6 +134,14 @@ func (h *statusSessionHandler) handleStatusRequest() {
 	})
 }
 
+func (h *statusSessionHandler) handleLegacyPing(p *packet.LegacyPing) {
+	pong := h.firePing(version.Legacy.Protocol)
+	if pong == nil {
+		return
+	}
+	_ = netmc.CloseWith(h.conn, packet.LegacyDisconnectFromPing(pong, p.Version))
+}
+
 func (h *statusSessionHandler) handleStatusPing(p *packet.StatusPing) {
 	// Just return again and close
 	defer h.conn.Close()
@@ -13,6 +13,7 @@ import (
 	"go.minekube.com/gate/pkg/gate/proto"
 	"go.minekube.com/gate/pkg/runtime/event"
 	"go.minekube.com/gate/pkg/util/uuid"
+	"go.uber.org/atomic"
 )
 
 type authSessionHandler struct {
@@ -24,6 +25,9 @@ type authSessionHandler struct {
 	onlineMode bool
 
 	connectedPlayer *connectedPlayer
+	// Whether the login success was sent to a 1.20.2+ client
+	// and the proxy is waiting for packet.LoginAcknowledged.
+	loginAcknowledgePending atomic.Bool
 }
 
 type playerRegistrar interface {
@@ -79,6 +83,7 @@ func (a *authSessionHandler) Activated() {
 	)
 	a.connectedPlayer = player
 	if !a.registrar.canRegisterConnection(player) {
+		loginFailed(loginAlreadyConnected)
 		player.Disconnect(alreadyConnected)
 		return
 	}
@@ -112,6 +117,7 @@ func (a *authSessionHandler) completeLoginProtocolPhaseAndInit(player *connected
 		}
 		if err = player.SetCompressionThreshold(threshold); err != nil {
 			a.log.Error(err, "Error setting compression threshold")
+			loginFailed(loginInternalError)
 			_ = a.inbound.disconnect(internalServerConnectionError)
 			return
 		}
@@ -128,6 +134,7 @@ func (a *authSessionHandler) completeLoginProtocolPhaseAndInit(player *connected
 			// Failsafe
 			if !crypto.SetHolder(playerKey, playerID) {
 				if a.onlineMode {
+					loginFailed(loginInvalidKey)
 					_ = a.inbound.disconnect(&component.Translation{
 						Key: "multiplayer.disconnect.invalid_public_key",
 					})
@@ -144,6 +151,11 @@ func (a *authSessionHandler) completeLoginProtocolPhaseAndInit(player *connected
 		}
 	}
 
+	// The 1.20.2+ client must acknowledge the login before switching to the config state.
+	// Set before writing the login success since the acknowledgement may arrive right after.
+	configPhase := player.Protocol().GreaterEqual(version.Minecraft_1_20_2)
+	a.loginAcknowledgePending.Store(configPhase)
+
 	if player.WritePacket(&packet.ServerLoginSuccess{
 		UUID:       playerID,
 		Username:   player.Username(),
@@ -152,7 +164,35 @@ func (a *authSessionHandler) completeLoginProtocolPhaseAndInit(player *connected
 		return
 	}
 
+	if configPhase {
+		return // continued by handleLoginAcknowledged
+	}
+
 	player.SetState(state.Play)
+	a.fireLoginEvent(player, func() netmc.SessionHandler {
+		return newInitialConnectSessionHandler(player)
+	})
+}
+
+// handleLoginAcknowledged switches a 1.20.2+ player to the config state
+// after the client acknowledged the login success.
+func (a *authSessionHandler) handleLoginAcknowledged() {
+	player := a.connectedPlayer
+	if player == nil || !a.loginAcknowledgePending.CompareAndSwap(true, false) {
+		_ = a.inbound.delegate.Close()
+		return
+	}
+	player.SetState(state.Config)
+	// The client sends its settings and brand right away,
+	// so the config session handler must be active before the LoginEvent.
+	player.SetSessionHandler(newClientConfigSessionHandler(player))
+	a.fireLoginEvent(player, nil)
+}
+
+// fireLoginEvent fires the LoginEvent and, if allowed, registers the player,
+// switches to the session handler returned by the optional newHandler and
+// connects the player to the initial server.
+func (a *authSessionHandler) fireLoginEvent(player *connectedPlayer, newHandler func() netmc.SessionHandler) {
 	loginEvent := &LoginEvent{player: player}
 	event.FireParallel(a.eventMgr, loginEvent, func(e *LoginEvent) {
 		if !player.Active() {
@@ -164,18 +204,23 @@ func (a *authSessionHandler) completeLoginProtocolPhaseAndInit(player *connected
 		}
 
 		if !loginEvent.Allowed() {
+			loginFailed(loginDenied)
 			player.Disconnect(loginEvent.Reason())
 			return
 		}
 
 		if !a.registrar.registerConnection(player) {
+			loginFailed(loginAlreadyConnected)
 			player.Disconnect(alreadyConnected)
 			return
 		}
+		loginSucceeded()
 
 		// Login is done now, just connect player to first server and
-		// let InitialConnectSessionHandler do further work.
-		player.SetSessionHandler(newInitialConnectSessionHandler(player))
+		// let the initial connect or client config session handler do further work.
+		if newHandler != nil {
+			player.SetSessionHandler(newHandler())
+		}
 		a.eventMgr.Fire(&PostLoginEvent{player: player})
 		a.connectToInitialServer(player)
 	})
@@ -196,7 +241,7 @@ func (a *authSessionHandler) connectToInitialServer(player *connectedPlayer) {
 		player.Disconnect(noAvailableServers) // Will call Disconnected() in InitialConnectSessionHandler
 		return
 	}
-	ctx, cancel := withConnectionTimeout(player.Context(), a.config())
+	ctx, cancel := withConnectionTimeout(player.Context(), a.config(), chooseServer.InitialServer())
 	defer cancel()
 	player.CreateConnectionRequest(chooseServer.InitialServer()).ConnectWithIndication(ctx)
 }
@@ -204,7 +249,11 @@ func (a *authSessionHandler) connectToInitialServer(player *connectedPlayer) {
 func (a *authSessionHandler) Deactivated() {}
 
 func (a *authSessionHandler) HandlePacket(pc *proto.PacketContext) {
-	// no packet expected during auth session
+	if _, ok := pc.Packet.(*packet.LoginAcknowledged); ok {
+		a.handleLoginAcknowledged()
+		return
+	}
+	// no other packet expected during auth session
 	_ = a.inbound.delegate.Close()
 }
 
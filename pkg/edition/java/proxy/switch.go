@@ -240,7 +240,7 @@ func (p *connectedPlayer) handleKickEvent(e *KickedFromServerEvent, friendlyReas
 	case *DisconnectPlayerKickResult:
 		p.Disconnect(result.Reason)
 	case *RedirectPlayerKickResult:
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.config().ConnectionTimeout)*time.Millisecond)
+		ctx, cancel := withConnectionTimeout(context.Background(), p.config(), result.Server)
 		defer cancel()
 		redirect, err := p.createConnectionRequest(result.Server).connect(ctx)
 		if err != nil {
@@ -391,7 +391,16 @@ func (c *connectionRequest) internalConnect(ctx context.Context) (result *connec
 	conn := newServerConnection(server, c.player)
 	c.player.setInFlightConnection(conn)
 	defer c.resetIfInFlightIs(conn)
-	return conn.connect(ctx)
+
+	start := time.Now()
+	result, err = conn.connect(ctx)
+	name := server.ServerInfo().Name()
+	if err != nil {
+		backendConnectErrors.WithLabelValues(name).Inc()
+	} else if result.Status().Successful() {
+		serverSwitchDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}
+	return result, err
 }
 
 func (c *connectionRequest) resetIfInFlightIs(establishedConnection *serverConnection) {
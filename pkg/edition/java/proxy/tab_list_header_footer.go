@@ -0,0 +1,244 @@
+package proxy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// tabListIdleInterval is the interval to re-read the cfg while the tab list is disabled.
+const tabListIdleInterval = 10 * time.Second
+
+// runTabListRefresh renders and sends the tab list header and footer to all
+// players in the configured interval until stop is closed.
+// The tab list cfg is read every round so that config reloads apply.
+func (p *Proxy) runTabListRefresh(stop <-chan struct{}) {
+	var prev *config.Config
+	for {
+		cfg := p.config()
+		interval := tabListIdleInterval
+		if cfg.TabList.Enabled {
+			p.forEachHeaderFooter(cfg, func(serverConn *serverConnection) {
+				// Skip the player if the previous refresh was not sent yet
+				if !serverConn.player.refreshingHeaderFooter.CompareAndSwap(false, true) {
+					return
+				}
+				defer serverConn.player.refreshingHeaderFooter.Store(false)
+				p.sendHeaderFooter(cfg, serverConn)
+			})
+			interval = time.Duration(cfg.TabList.RefreshInterval) * time.Millisecond
+		} else if prev != nil && prev.TabList.Enabled {
+			// Disabled by a config reload
+			p.forEachHeaderFooter(prev, restoreHeaderFooter)
+		}
+		prev = cfg
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// forEachHeaderFooter calls fn in a new goroutine for the server connection of
+// each player whose tab list header and footer is rendered according to cfg,
+// so that a slow player does not block the others.
+func (p *Proxy) forEachHeaderFooter(cfg *config.Config, fn func(serverConn *serverConnection)) {
+	for _, player := range p.Players() {
+		player, ok := player.(*connectedPlayer)
+		if !ok {
+			continue
+		}
+		if serverConn := player.connectedServer(); serverConn != nil &&
+			cfg.TabListPolicyFor(serverConn.server.ServerInfo().Name()) != config.PassthroughTabListPolicy {
+			go fn(serverConn)
+		}
+	}
+}
+
+// restoreHeaderFooter replaces the rendered tab list header and footer with the ones
+// last sent by the backend server or clears them if the server sent none.
+func restoreHeaderFooter(serverConn *serverConnection) {
+	tabList := serverConn.player.tabList
+	header, footer := serverConn.headerFooter()
+	var err error
+	if header == nil && footer == nil {
+		err = tabList.ClearHeaderFooter()
+	} else {
+		if header == nil {
+			header = &component.Text{}
+		}
+		if footer == nil {
+			footer = &component.Text{}
+		}
+		err = tabList.SetHeaderFooter(header, footer)
+	}
+	if err != nil {
+		serverConn.player.log.V(1).Info("error restoring tab list header and footer", "error", err)
+	}
+}
+
+// initHeaderFooter is called when the player connected to serverConn and shows the
+// rendered header and footer right away or clears them if the backend server sends its own.
+func (p *Proxy) initHeaderFooter(serverConn *serverConnection) {
+	cfg := p.config()
+	if !cfg.TabList.Enabled {
+		return
+	}
+	if cfg.TabListPolicyFor(serverConn.server.ServerInfo().Name()) == config.PassthroughTabListPolicy {
+		_ = serverConn.player.tabList.ClearHeaderFooter()
+		return
+	}
+	p.sendHeaderFooter(cfg, serverConn)
+}
+
+// sendHeaderFooter renders the tab list header and footer templates for
+// the player of serverConn and merges them with the ones sent by the backend
+// server if the server's policy is config.MergeTabListPolicy.
+func (p *Proxy) sendHeaderFooter(cfg *config.Config, serverConn *serverConnection) {
+	player := serverConn.player
+	header, footer, err := p.renderHeaderFooter(cfg, player, serverConn.server)
+	if err != nil {
+		player.log.Error(err, "error rendering tab list header and footer")
+		return
+	}
+	if cfg.TabListPolicyFor(serverConn.server.ServerInfo().Name()) == config.MergeTabListPolicy {
+		backendHeader, backendFooter := serverConn.headerFooter()
+		header = joinLines(header, backendHeader)
+		footer = joinLines(backendFooter, footer)
+	}
+	if err = player.tabList.SetHeaderFooter(header, footer); err != nil {
+		player.log.V(1).Info("error sending tab list header and footer", "error", err)
+	}
+}
+
+// renderHeaderFooter replaces the placeholders of the tab list header and footer templates.
+//
+// The placeholder values are names and numbers that never need to
+// be escaped, so the templates can be in legacy or json format.
+func (p *Proxy) renderHeaderFooter(
+	cfg *config.Config,
+	player *connectedPlayer,
+	server RegisteredServer,
+) (header, footer component.Component, err error) {
+	ping := player.Ping().Milliseconds()
+	if ping < 0 {
+		ping = 0 // unknown yet
+	}
+	r := strings.NewReplacer(
+		"{player}", player.Username(),
+		"{server}", server.ServerInfo().Name(),
+		"{online}", strconv.Itoa(p.PlayerCount()),
+		"{server_online}", strconv.Itoa(server.Players().Len()),
+		"{max}", strconv.Itoa(cfg.Status.ShowMaxPlayers),
+		"{ping}", strconv.FormatInt(ping, 10),
+		"{time}", time.Now().Format("15:04:05"),
+	)
+	header, err = parseTabListTemplate(r.Replace(cfg.TabList.Header))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing header: %w", err)
+	}
+	footer, err = parseTabListTemplate(r.Replace(cfg.TabList.Footer))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing footer: %w", err)
+	}
+	return header, footer, nil
+}
+
+// checkTabListTemplates returns an error if the tab list header or footer template is invalid.
+func checkTabListTemplates(c *config.TabList) error {
+	// Replace placeholders like when rendering, a legacy template may start with one
+	r := strings.NewReplacer(
+		"{player}", "", "{server}", "", "{online}", "0", "{server_online}", "0",
+		"{max}", "0", "{ping}", "0", "{time}", "",
+	)
+	if _, err := parseTabListTemplate(r.Replace(c.Header)); err != nil {
+		return fmt.Errorf("error parsing header: %w", err)
+	}
+	if _, err := parseTabListTemplate(r.Replace(c.Footer)); err != nil {
+		return fmt.Errorf("error parsing footer: %w", err)
+	}
+	return nil
+}
+
+func parseTabListTemplate(s string) (component.Component, error) {
+	if s == "" {
+		return &component.Text{}, nil
+	}
+	return parseTextComponentFromConfig(s)
+}
+
+// joinLines joins the components separated by a newline, b is nil-able.
+func joinLines(a, b component.Component) component.Component {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	return &component.Text{Extra: []component.Component{a, &component.Text{Content: "\n"}, b}}
+}
+
+// handleHeaderAndFooter handles the tab list header and footer
+// sent by the backend server according to the server's policy.
+func (b *backendPlaySessionHandler) handleHeaderAndFooter(p *packet.HeaderAndFooter, pc *proto.PacketContext) {
+	cfg := b.proxy().config()
+	if !cfg.TabList.Enabled {
+		b.forwardToPlayer(pc, nil)
+		return
+	}
+	policy := cfg.TabListPolicyFor(b.serverConn.server.ServerInfo().Name())
+	if policy == config.PassthroughTabListPolicy {
+		b.forwardToPlayer(pc, nil)
+		return
+	}
+	// Remember it to merge it or to restore it when the tab list is disabled
+	if err := b.serverConn.setHeaderFooter(p, pc.Protocol); err != nil {
+		b.serverConn.log.V(1).Info("error decoding tab list header and footer from backend", "error", err)
+		return
+	}
+	if policy == config.MergeTabListPolicy {
+		b.proxy().sendHeaderFooter(cfg, b.serverConn)
+	}
+	// Otherwise drop it, the rendered header and footer are shown instead
+}
+
+// headerFooter returns the last tab list header and
+// footer sent by the backend server, both nil-able.
+func (s *serverConnection) headerFooter() (header, footer component.Component) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.header, s.footer
+}
+
+func (s *serverConnection) setHeaderFooter(p *packet.HeaderAndFooter, protocol proto.Protocol) error {
+	header, err := decodeHeaderFooter(p.Header, protocol)
+	if err != nil {
+		return fmt.Errorf("error decoding header: %w", err)
+	}
+	footer, err := decodeHeaderFooter(p.Footer, protocol)
+	if err != nil {
+		return fmt.Errorf("error decoding footer: %w", err)
+	}
+	s.mu.Lock()
+	s.header, s.footer = header, footer
+	s.mu.Unlock()
+	return nil
+}
+
+// decodeHeaderFooter decodes a json header or footer and
+// returns nil if it is empty, e.g. of packet.ResetHeaderAndFooter.
+func decodeHeaderFooter(s string, protocol proto.Protocol) (component.Component, error) {
+	if s == "" || s == packet.ResetHeaderAndFooter.Header {
+		return nil, nil
+	}
+	return util.JsonCodec(protocol).Unmarshal([]byte(s))
+}
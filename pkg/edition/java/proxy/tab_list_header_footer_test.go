@@ -0,0 +1,191 @@
+package proxy
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/profile"
+	"go.minekube.com/gate/pkg/edition/java/proto/packet"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
+	"go.minekube.com/gate/pkg/edition/java/proxy/tablist"
+	"go.minekube.com/gate/pkg/gate/proto"
+)
+
+// recordingTabList is a tablist.TabList that records the header and footer set.
+type recordingTabList struct {
+	tablist.TabList
+
+	mu             sync.Mutex
+	sets, clears   int
+	header, footer component.Component
+}
+
+func (t *recordingTabList) SetHeaderFooter(header, footer component.Component) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sets++
+	t.header, t.footer = header, footer
+	return nil
+}
+
+func (t *recordingTabList) ClearHeaderFooter() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clears++
+	t.header, t.footer = nil, nil
+	return nil
+}
+
+type tabListTest struct {
+	proxy      *Proxy
+	cfg        *config.Config
+	conn       *recordingConn
+	tabList    *recordingTabList
+	serverConn *serverConnection
+	handler    *backendPlaySessionHandler
+}
+
+func newTabListTest(t *testing.T, enabled bool, policy config.TabListPolicy) *tabListTest {
+	cfg := config.DefaultConfig
+	cfg.TabList.Enabled = enabled
+	cfg.TabList.Header = "§bGate {server}"
+	cfg.TabList.Footer = "§7{player}"
+	cfg.Servers = map[string]config.Server{
+		"lobby": {Address: "127.0.0.1:25566", TabListPolicy: policy},
+	}
+	p := newTestProxy(t, cfg)
+	conn := &recordingConn{protocol: version.MaximumVersion.Protocol}
+	tabList := &recordingTabList{}
+	player := &connectedPlayer{
+		MinecraftConn:      conn,
+		sessionHandlerDeps: &sessionHandlerDeps{proxy: p, configProvider: p},
+		log:                logr.Discard(),
+		profile:            &profile.GameProfile{Name: "test"},
+		tabList:            tabList,
+	}
+	server := newRegisteredServer(NewServerInfo("lobby", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 25566}))
+	serverConn := newServerConnection(server, player)
+	return &tabListTest{
+		proxy:      p,
+		cfg:        p.config(),
+		conn:       conn,
+		tabList:    tabList,
+		serverConn: serverConn,
+		handler:    &backendPlaySessionHandler{serverConn: serverConn},
+	}
+}
+
+// receive lets the player receive a tab list header and footer from the backend server.
+func (tt *tabListTest) receive(header, footer string) {
+	tt.handler.handleHeaderAndFooter(
+		&packet.HeaderAndFooter{Header: header, Footer: footer},
+		&proto.PacketContext{
+			Direction:   proto.ClientBound,
+			Protocol:    tt.conn.protocol,
+			KnownPacket: true,
+			Payload:     []byte{0x42},
+		},
+	)
+}
+
+func (tt *tabListTest) rendered(t *testing.T) (header, footer component.Component) {
+	header, footer, err := tt.proxy.renderHeaderFooter(tt.cfg, tt.serverConn.player, tt.serverConn.server)
+	require.NoError(t, err)
+	return header, footer
+}
+
+const (
+	backendHeader = `{"text":"Backend header"}`
+	backendFooter = `{"text":"Backend footer"}`
+)
+
+func TestTabListPolicy_override(t *testing.T) {
+	tt := newTabListTest(t, true, config.OverrideTabListPolicy)
+	tt.proxy.initHeaderFooter(tt.serverConn)
+	header, footer := tt.rendered(t)
+	require.Equal(t, header, tt.tabList.header)
+	require.Equal(t, footer, tt.tabList.footer)
+
+	tt.receive(backendHeader, backendFooter)
+	require.Empty(t, tt.conn.forwarded(), "backend header and footer must be dropped")
+	require.Equal(t, 1, tt.tabList.sets)
+	require.Equal(t, header, tt.tabList.header)
+
+	// Still remembered to be restored
+	backendH, backendF := tt.serverConn.headerFooter()
+	require.NotNil(t, backendH)
+	require.NotNil(t, backendF)
+}
+
+func TestTabListPolicy_merge(t *testing.T) {
+	tt := newTabListTest(t, true, config.MergeTabListPolicy)
+	tt.receive(backendHeader, backendFooter)
+	require.Empty(t, tt.conn.forwarded(), "backend header and footer must be merged")
+
+	header, footer := tt.rendered(t)
+	backendH, backendF := tt.serverConn.headerFooter()
+	require.NotNil(t, backendH)
+	require.NotNil(t, backendF)
+	require.Equal(t, joinLines(header, backendH), tt.tabList.header)
+	require.Equal(t, joinLines(backendF, footer), tt.tabList.footer)
+
+	// A reset by the backend leaves the rendered header and footer only
+	tt.receive(packet.ResetHeaderAndFooter.Header, packet.ResetHeaderAndFooter.Footer)
+	require.Equal(t, header, tt.tabList.header)
+	require.Equal(t, footer, tt.tabList.footer)
+}
+
+func TestTabListPolicy_passthrough(t *testing.T) {
+	tt := newTabListTest(t, true, config.PassthroughTabListPolicy)
+	tt.proxy.initHeaderFooter(tt.serverConn)
+	require.Equal(t, 1, tt.tabList.clears)
+
+	tt.receive(backendHeader, backendFooter)
+	require.Equal(t, [][]byte{{0x42}}, tt.conn.forwarded())
+	require.Zero(t, tt.tabList.sets)
+	backendH, backendF := tt.serverConn.headerFooter()
+	require.Nil(t, backendH)
+	require.Nil(t, backendF)
+}
+
+func TestTabList_disabled(t *testing.T) {
+	tt := newTabListTest(t, false, config.OverrideTabListPolicy)
+	tt.proxy.initHeaderFooter(tt.serverConn)
+	tt.receive(backendHeader, backendFooter)
+	require.Equal(t, [][]byte{{0x42}}, tt.conn.forwarded())
+	require.Zero(t, tt.tabList.sets)
+	require.Zero(t, tt.tabList.clears)
+}
+
+func TestRestoreHeaderFooter(t *testing.T) {
+	t.Run("backend header and footer", func(t *testing.T) {
+		tt := newTabListTest(t, true, config.OverrideTabListPolicy)
+		tt.receive(backendHeader, backendFooter)
+		restoreHeaderFooter(tt.serverConn)
+		backendH, backendF := tt.serverConn.headerFooter()
+		require.Equal(t, backendH, tt.tabList.header)
+		require.Equal(t, backendF, tt.tabList.footer)
+	})
+	t.Run("backend header only", func(t *testing.T) {
+		tt := newTabListTest(t, true, config.OverrideTabListPolicy)
+		tt.receive(backendHeader, "")
+		restoreHeaderFooter(tt.serverConn)
+		backendH, _ := tt.serverConn.headerFooter()
+		require.Equal(t, backendH, tt.tabList.header)
+		require.Equal(t, &component.Text{}, tt.tabList.footer)
+	})
+	t.Run("nothing sent by backend", func(t *testing.T) {
+		tt := newTabListTest(t, true, config.OverrideTabListPolicy)
+		tt.proxy.initHeaderFooter(tt.serverConn)
+		restoreHeaderFooter(tt.serverConn)
+		require.Equal(t, 1, tt.tabList.clears)
+		require.Nil(t, tt.tabList.header)
+		require.Nil(t, tt.tabList.footer)
+	})
+}
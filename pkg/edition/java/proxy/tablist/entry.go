@@ -152,3 +152,15 @@ func newPlayerListItemEntry(entry Entry) *packet.PlayerListItemEntry {
 		PlayerKey:   entry.IdentifiedKey(),
 	}
 }
+
+func newUpsertPlayerInfoEntry(entry Entry) *packet.UpsertPlayerInfoEntry {
+	p := entry.Profile()
+	return &packet.UpsertPlayerInfoEntry{
+		ID:          p.ID,
+		Profile:     &p,
+		Listed:      true,
+		Latency:     int(entry.Latency().Milliseconds()),
+		GameMode:    entry.GameMode(),
+		DisplayName: entry.DisplayName(),
+	}
+}
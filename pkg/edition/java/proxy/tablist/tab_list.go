@@ -11,6 +11,7 @@ import (
 	"go.minekube.com/gate/pkg/edition/java/profile"
 	"go.minekube.com/gate/pkg/edition/java/proto/packet"
 	"go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proto/version"
 	"go.minekube.com/gate/pkg/edition/java/proxy/crypto"
 	"go.minekube.com/gate/pkg/edition/java/proxy/crypto/keyrevision"
 	"go.minekube.com/gate/pkg/gate/proto"
@@ -27,6 +28,8 @@ type TabList interface {
 	HasEntry(id uuid.UUID) bool                               // Determines if the specified entry exists in the tab list.
 	Entries() map[uuid.UUID]Entry                             // Returns the entries in the tab list.
 	ProcessBackendPacket(*packet.PlayerListItem) error        // Processes a packet.PlayerListItem sent from the backend to the client.
+	ProcessUpsert(*packet.UpsertPlayerInfo) error             // Processes a packet.UpsertPlayerInfo sent from the backend to the client (1.19.3+).
+	ProcessRemove(*packet.RemovePlayerInfo) error             // Processes a packet.RemovePlayerInfo sent from the backend to the client (1.19.3+).
 }
 
 type (
@@ -90,6 +93,18 @@ func (t *tabList) AddEntry(entry Entry) error {
 	}
 	t.entries[entry.Profile().ID] = e
 	t.mu.Unlock()
+	if t.protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		return t.w.WritePacket(&packet.UpsertPlayerInfo{
+			ActionSet: []packet.UpsertPlayerInfoAction{
+				packet.AddPlayerUpsertPlayerInfoAction,
+				packet.UpdateGameModeUpsertPlayerInfoAction,
+				packet.UpdateListedUpsertPlayerInfoAction,
+				packet.UpdateLatencyUpsertPlayerInfoAction,
+				packet.UpdateDisplayNameUpsertPlayerInfoAction,
+			},
+			Entries: []*packet.UpsertPlayerInfoEntry{newUpsertPlayerInfoEntry(entry)},
+		})
+	}
 	return t.w.WritePacket(&packet.PlayerListItem{
 		Action: packet.AddPlayerListItemAction,
 		Items:  []packet.PlayerListItemEntry{*newPlayerListItemEntry(entry)},
@@ -107,6 +122,11 @@ func (t *tabList) removeEntry(id uuid.UUID) (*tabListEntry, error) {
 	delete(t.entries, id)
 	t.mu.Unlock()
 	if ok {
+		if t.protocol.GreaterEqual(version.Minecraft_1_19_3) {
+			return entry, t.w.WritePacket(&packet.RemovePlayerInfo{
+				PlayersToRemove: []uuid.UUID{id},
+			})
+		}
 		return entry, t.w.WritePacket(&packet.PlayerListItem{
 			Action: packet.RemovePlayerListItemAction,
 			Items:  []packet.PlayerListItemEntry{*newPlayerListItemEntry(entry)},
@@ -157,6 +177,13 @@ func (t *tabList) clearEntries(bufferPacket func(proto.Packet) error) error {
 		return nil
 	}
 
+	if t.protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		ids := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return bufferPacket(&packet.RemovePlayerInfo{PlayersToRemove: ids})
+	}
 	return bufferPacket(&packet.PlayerListItem{
 		Action: packet.RemovePlayerListItemAction,
 		Items:  items,
@@ -247,10 +274,78 @@ func (t *tabList) ProcessBackendPacket(p *packet.PlayerListItem) error {
 	return nil
 }
 
+func (t *tabList) ProcessUpsert(p *packet.UpsertPlayerInfo) error {
+	// Packet is already forwarded on, so no need to do that here
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, item := range p.Entries {
+		if p.ContainsAction(packet.AddPlayerUpsertPlayerInfoAction) {
+			if item.Profile == nil {
+				return errors.New("got null game profile for AddPlayerUpsertPlayerInfoAction")
+			}
+			if _, ok := t.entries[item.ID]; !ok {
+				t.entries[item.ID] = &tabListEntry{
+					tabList: t,
+					profile: item.Profile,
+				}
+			}
+		}
+		e, ok := t.entries[item.ID]
+		if !ok {
+			// Sometimes updates are sent before AddPlayer so don't want to warn here
+			continue
+		}
+		for _, action := range p.ActionSet {
+			switch action {
+			case packet.UpdateGameModeUpsertPlayerInfoAction:
+				e.setGameMode(item.GameMode)
+			case packet.UpdateLatencyUpsertPlayerInfoAction:
+				e.setLatency(time.Millisecond * time.Duration(item.Latency))
+			case packet.UpdateDisplayNameUpsertPlayerInfoAction:
+				e.setDisplayNameNoUpdate(item.DisplayName)
+			case packet.InitializeChatUpsertPlayerInfoAction:
+				if item.ChatSession != nil {
+					e.mu.Lock()
+					e.playerKey = item.ChatSession.Key
+					e.mu.Unlock()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (t *tabList) ProcessRemove(p *packet.RemovePlayerInfo) error {
+	// Packet is already forwarded on, so no need to do that here
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range p.PlayersToRemove {
+		delete(t.entries, id)
+	}
+	return nil
+}
+
 func (t *tabList) updateEntry(action packet.PlayerListItemAction, entry *tabListEntry) error {
 	if !t.HasEntry(entry.Profile().ID) {
 		return nil
 	}
+	if t.protocol.GreaterEqual(version.Minecraft_1_19_3) {
+		var upsertAction packet.UpsertPlayerInfoAction
+		switch action {
+		case packet.UpdateGameModePlayerListItemAction:
+			upsertAction = packet.UpdateGameModeUpsertPlayerInfoAction
+		case packet.UpdateLatencyPlayerListItemAction:
+			upsertAction = packet.UpdateLatencyUpsertPlayerInfoAction
+		case packet.UpdateDisplayNamePlayerListItemAction:
+			upsertAction = packet.UpdateDisplayNameUpsertPlayerInfoAction
+		default:
+			return fmt.Errorf("unsupported update action %d", action)
+		}
+		return t.w.WritePacket(&packet.UpsertPlayerInfo{
+			ActionSet: []packet.UpsertPlayerInfoAction{upsertAction},
+			Entries:   []*packet.UpsertPlayerInfoEntry{newUpsertPlayerInfoEntry(entry)},
+		})
+	}
 	packetItem := newPlayerListItemEntry(entry)
 
 	selectedKey := packetItem.PlayerKey
@@ -0,0 +1,171 @@
+// Package query implements the GameSpy4 (UT3) query protocol
+// used by Minecraft servers to report their status over UDP.
+//
+// See https://wiki.vg/Query for the protocol documentation.
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Packet types of the query protocol.
+const (
+	HandshakeType byte = 0x09
+	StatType      byte = 0x00
+)
+
+// magic is the prefix of every query request.
+var magic = []byte{0xFE, 0xFD}
+
+// sessionIDMask must be applied to a session id
+// since the Minecraft client ignores the upper nibbles.
+const sessionIDMask = 0x0F0F0F0F
+
+var (
+	// Constant padding sequences of the full stat response.
+	fullStatPadding1 = []byte{0x73, 0x70, 0x6C, 0x69, 0x74, 0x6E, 0x75, 0x6D, 0x00, 0x80, 0x00} // "splitnum\0\x80\0"
+	fullStatPadding2 = []byte{0x01, 0x70, 0x6C, 0x61, 0x79, 0x65, 0x72, 0x5F, 0x00, 0x00}       // "\x01player_\0\0"
+)
+
+// ErrInvalidRequest indicates a malformed query request.
+var ErrInvalidRequest = errors.New("invalid query request")
+
+// Request is a decoded query request.
+type Request struct {
+	Type      byte  // HandshakeType or StatType
+	SessionID int32 // The client generated session id.
+	// Challenge is the token the client received in the handshake response.
+	// Only set for StatType requests.
+	Challenge int32
+	// Full indicates whether the client requested the full stat.
+	// Only set for StatType requests.
+	Full bool
+}
+
+// ParseRequest decodes a query request from a datagram.
+func ParseRequest(b []byte) (*Request, error) {
+	if len(b) < 7 || !bytes.Equal(b[:2], magic) {
+		return nil, ErrInvalidRequest
+	}
+	req := &Request{
+		Type:      b[2],
+		SessionID: int32(binary.BigEndian.Uint32(b[3:7])) & sessionIDMask,
+	}
+	switch req.Type {
+	case HandshakeType:
+		return req, nil
+	case StatType:
+		b = b[7:]
+		if len(b) < 4 {
+			return nil, fmt.Errorf("%w: missing challenge token", ErrInvalidRequest)
+		}
+		req.Challenge = int32(binary.BigEndian.Uint32(b[:4]))
+		// Full stat requests are padded with 4 additional bytes
+		req.Full = len(b) == 8
+		return req, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type 0x%02x", ErrInvalidRequest, req.Type)
+	}
+}
+
+// WriteHandshake writes a handshake response containing the challenge token.
+func WriteHandshake(w io.Writer, sessionID, challenge int32) error {
+	b := new(bytes.Buffer)
+	b.WriteByte(HandshakeType)
+	_ = binary.Write(b, binary.BigEndian, sessionID)
+	writeString(b, strconv.FormatInt(int64(challenge), 10))
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Response is the server information sent in reply to a stat request.
+type Response struct {
+	Hostname    string   // The message of the day in plain legacy format.
+	GameVersion string   // The supported game versions.
+	Map         string   // The name of the map.
+	Players     int      // Online player count.
+	MaxPlayers  int      // Maximum player count.
+	ProxyHost   string   // The host the proxy is listening on.
+	ProxyPort   uint16   // The port the proxy is listening on.
+	PlayerNames []string // Online player names, only sent in the full stat.
+	// ServerVersion is the software version reported together with Plugins.
+	ServerVersion string
+	Plugins       []string // Plugin names, only sent in the full stat.
+}
+
+const (
+	gameType = "SMP"
+	gameID   = "MINECRAFT"
+)
+
+// WriteBasic writes a basic stat response.
+func (r *Response) WriteBasic(w io.Writer, sessionID int32) error {
+	b := new(bytes.Buffer)
+	b.WriteByte(StatType)
+	_ = binary.Write(b, binary.BigEndian, sessionID)
+	writeString(b, r.Hostname)
+	writeString(b, gameType)
+	writeString(b, r.Map)
+	writeString(b, strconv.Itoa(r.Players))
+	writeString(b, strconv.Itoa(r.MaxPlayers))
+	_ = binary.Write(b, binary.LittleEndian, r.ProxyPort)
+	writeString(b, r.ProxyHost)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteFull writes a full stat response.
+func (r *Response) WriteFull(w io.Writer, sessionID int32) error {
+	b := new(bytes.Buffer)
+	b.WriteByte(StatType)
+	_ = binary.Write(b, binary.BigEndian, sessionID)
+	b.Write(fullStatPadding1)
+
+	kv := func(k, v string) { writeString(b, k); writeString(b, v) }
+	kv("hostname", r.Hostname)
+	kv("gametype", gameType)
+	kv("game_id", gameID)
+	kv("version", r.GameVersion)
+	kv("plugins", r.pluginsString())
+	kv("map", r.Map)
+	kv("numplayers", strconv.Itoa(r.Players))
+	kv("maxplayers", strconv.Itoa(r.MaxPlayers))
+	kv("hostport", strconv.Itoa(int(r.ProxyPort)))
+	kv("hostip", r.ProxyHost)
+	b.WriteByte(0x00) // end of key-value section
+
+	b.Write(fullStatPadding2)
+	for _, name := range r.PlayerNames {
+		writeString(b, name)
+	}
+	b.WriteByte(0x00) // end of player section
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pluginsString formats the server version and plugins
+// as in "<version>: <plugin1>; <plugin2>".
+func (r *Response) pluginsString() string {
+	if len(r.Plugins) == 0 {
+		return r.ServerVersion
+	}
+	return r.ServerVersion + ": " + strings.Join(r.Plugins, "; ")
+}
+
+// writeString writes s as null-terminated ISO-8859-1 string,
+// unsupported characters are replaced with '?'.
+func writeString(b *bytes.Buffer, s string) {
+	for _, r := range s {
+		if r > 0xFF {
+			r = '?'
+		}
+		b.WriteByte(byte(r))
+	}
+	b.WriteByte(0x00)
+}
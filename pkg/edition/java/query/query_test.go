@@ -0,0 +1,68 @@
+package query
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequest(t *testing.T) {
+	req, err := ParseRequest([]byte{0xFE, 0xFD, 0x09, 0x00, 0x00, 0x00, 0x01})
+	require.NoError(t, err)
+	require.Equal(t, &Request{Type: HandshakeType, SessionID: 1}, req)
+
+	basic := []byte{0xFE, 0xFD, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x91, 0x29, 0x5B}
+	req, err = ParseRequest(basic)
+	require.NoError(t, err)
+	require.Equal(t, &Request{Type: StatType, SessionID: 0x0F0F0F0F, Challenge: 9513307}, req)
+
+	req, err = ParseRequest(append(basic, 0x00, 0x00, 0x00, 0x00))
+	require.NoError(t, err)
+	require.True(t, req.Full)
+
+	_, err = ParseRequest([]byte{0xFE, 0xFD, 0x00, 0x00, 0x00, 0x00, 0x01})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = ParseRequest([]byte{0x00, 0x01})
+	require.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestWriteHandshake(t *testing.T) {
+	b := new(bytes.Buffer)
+	require.NoError(t, WriteHandshake(b, 1, 9513307))
+	require.Equal(t, append([]byte{0x09, 0x00, 0x00, 0x00, 0x01}, "9513307\x00"...), b.Bytes())
+}
+
+func TestResponse(t *testing.T) {
+	r := &Response{
+		Hostname:      "A Gate §bProxy",
+		GameVersion:   "1.7.2-1.19.2",
+		Map:           "world",
+		Players:       2,
+		MaxPlayers:    20,
+		ProxyHost:     "0.0.0.0",
+		ProxyPort:     25565,
+		PlayerNames:   []string{"a", "b"},
+		ServerVersion: "Gate",
+		Plugins:       []string{"p1", "p2"},
+	}
+
+	b := new(bytes.Buffer)
+	require.NoError(t, r.WriteBasic(b, 1))
+	expected := []byte{0x00, 0x00, 0x00, 0x00, 0x01}
+	expected = append(expected, "A Gate \xa7bProxy\x00SMP\x00world\x002\x0020\x00"...)
+	expected = append(expected, 0xDD, 0x63)
+	expected = append(expected, "0.0.0.0\x00"...)
+	require.Equal(t, expected, b.Bytes())
+
+	b.Reset()
+	require.NoError(t, r.WriteFull(b, 1))
+	expected = []byte{0x00, 0x00, 0x00, 0x00, 0x01}
+	expected = append(expected, fullStatPadding1...)
+	expected = append(expected, "hostname\x00A Gate \xa7bProxy\x00gametype\x00SMP\x00game_id\x00MINECRAFT\x00"+
+		"version\x001.7.2-1.19.2\x00plugins\x00Gate: p1; p2\x00map\x00world\x00numplayers\x002\x00"+
+		"maxplayers\x0020\x00hostport\x0025565\x00hostip\x000.0.0.0\x00\x00"...)
+	expected = append(expected, fullStatPadding2...)
+	expected = append(expected, "a\x00b\x00\x00"...)
+	require.Equal(t, expected, b.Bytes())
+}
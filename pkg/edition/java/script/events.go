@@ -0,0 +1,49 @@
+package script
+
+import (
+	"go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+// eventTypes are the proxy events plugins can subscribe to by their type name.
+var eventTypes = typesByName(
+	(*proxy.PingEvent)(nil),
+	(*proxy.ConnectionHandshakeEvent)(nil),
+	(*proxy.GameProfileRequestEvent)(nil),
+	(*proxy.PlayerModInfoEvent)(nil),
+	(*proxy.PermissionsSetupEvent)(nil),
+	(*proxy.PreLoginEvent)(nil),
+	(*proxy.LoginEvent)(nil),
+	(*proxy.DisconnectEvent)(nil),
+	(*proxy.PostLoginEvent)(nil),
+	(*proxy.PlayerChooseInitialServerEvent)(nil),
+	(*proxy.ServerPreConnectEvent)(nil),
+	(*proxy.KickedFromServerEvent)(nil),
+	(*proxy.ServerConnectedEvent)(nil),
+	(*proxy.ServerPostConnectEvent)(nil),
+	(*proxy.PluginMessageEvent)(nil),
+	(*proxy.PlayerSettingsChangedEvent)(nil),
+	(*proxy.PlayerChatEvent)(nil),
+	(*proxy.CommandExecuteEvent)(nil),
+	(*proxy.TabCompleteEvent)(nil),
+	(*proxy.PlayerAvailableCommandsEvent)(nil),
+	(*proxy.PlayerResourcePackStatusEvent)(nil),
+	(*proxy.ServerResourcePackSendEvent)(nil),
+	(*proxy.PlayerClientBrandEvent)(nil),
+	(*proxy.PlayerChannelRegisterEvent)(nil),
+	(*proxy.ServerLoginPluginMessageEvent)(nil),
+	(*proxy.PreShutdownEvent)(nil),
+	(*proxy.ReadyEvent)(nil),
+	(*proxy.ShutdownEvent)(nil),
+	(*proxy.ConfigUpdateEvent)(nil),
+	(*proxy.ServerStatusChangeEvent)(nil),
+)
+
+func typesByName(events ...event.Event) map[string]event.Type {
+	m := make(map[string]event.Type, len(events))
+	for _, e := range events {
+		t := event.TypeOf(e)
+		m[t.Elem().Name()] = t
+	}
+	return m
+}
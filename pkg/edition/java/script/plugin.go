@@ -0,0 +1,293 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/go-logr/logr"
+	"go.minekube.com/common/minecraft/component"
+	"go.minekube.com/common/minecraft/component/codec/legacy"
+
+	protoutil "go.minekube.com/gate/pkg/edition/java/proto/util"
+	"go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/runtime/event"
+	"go.minekube.com/gate/pkg/util/uuid"
+)
+
+// callTimeout is the maximum duration of a call into a plugin,
+// e.g. an event handler, before its script is interrupted.
+var callTimeout = 5 * time.Second
+
+// eventBusyTimeout is the maximum duration an event handler waits for another
+// call into the same plugin to finish. It is much shorter than the callTimeout
+// since the event is not dispatched further while the handler waits.
+var eventBusyTimeout = 100 * time.Millisecond
+
+var (
+	errPluginBusy     = errors.New("plugin is busy")
+	errPluginUnloaded = errors.New("plugin is unloaded")
+)
+
+// plugin is a loaded script running in its own JavaScript runtime.
+type plugin struct {
+	name   string
+	log    logr.Logger
+	loader *Loader
+	vm     *goja.Runtime
+	// Serializes calls into the runtime since it is not goroutine-safe.
+	// A call waiting longer than the callTimeout (the eventBusyTimeout for
+	// event handlers) fails with errPluginBusy,
+	// e.g. when a handler synchronously causes another call into the plugin.
+	sem chan struct{}
+
+	mu          sync.Mutex // Protects following fields
+	unloaded    bool
+	unsubscribe []func()
+	commands    []string // names of the registered commands
+	onDisable   goja.Callable
+}
+
+// newPlugin runs the script src and returns the plugin.
+func newPlugin(l *Loader, name, src string) (*plugin, error) {
+	p := &plugin{
+		name:   name,
+		log:    l.log.WithValues("plugin", name),
+		loader: l,
+		vm:     goja.New(),
+		sem:    make(chan struct{}, 1),
+	}
+	p.vm.SetFieldNameMapper(goja.UncapFieldNameMapper())
+	if err := p.bind(); err != nil {
+		return nil, err
+	}
+
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+	if _, err := p.run(func() (goja.Value, error) {
+		return p.vm.RunScript(name+ext, src)
+	}); err != nil {
+		p.release()
+		return nil, err
+	}
+	return p, nil
+}
+
+// call calls the script function fn with args converted to JavaScript values.
+func (p *plugin) call(fn goja.Callable, args ...any) (goja.Value, error) {
+	return p.callWait(callTimeout, fn, args...)
+}
+
+// callWait is like call but fails with errPluginBusy if
+// another call into the plugin does not finish within wait.
+func (p *plugin) callWait(wait time.Duration, fn goja.Callable, args ...any) (goja.Value, error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-time.After(wait):
+		return nil, errPluginBusy
+	}
+	defer func() { <-p.sem }()
+	if p.isUnloaded() {
+		return nil, errPluginUnloaded
+	}
+	return p.run(func() (goja.Value, error) {
+		values := make([]goja.Value, len(args))
+		for i, arg := range args {
+			values[i] = p.vm.ToValue(arg)
+		}
+		return fn(goja.Undefined(), values...)
+	})
+}
+
+// run runs fn and interrupts the script if it exceeds the callTimeout.
+// The caller must hold the sem.
+func (p *plugin) run(fn func() (goja.Value, error)) (v goja.Value, err error) {
+	t := time.AfterFunc(callTimeout, func() {
+		p.vm.Interrupt(fmt.Errorf("exceeded timeout of %s", callTimeout))
+	})
+	defer func() {
+		t.Stop()
+		p.vm.ClearInterrupt()
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+func (p *plugin) isUnloaded() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.unloaded
+}
+
+// unload runs the plugin's onDisable hook and releases its event handlers and commands.
+func (p *plugin) unload() {
+	p.mu.Lock()
+	onDisable := p.onDisable
+	p.mu.Unlock()
+	if onDisable != nil {
+		if _, err := p.call(onDisable); err != nil {
+			p.log.Error(err, "error disabling plugin")
+		}
+	}
+	p.release()
+}
+
+// release unsubscribes the event handlers and unregisters the commands of the plugin.
+func (p *plugin) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unloaded {
+		return
+	}
+	p.unloaded = true
+	for _, unsubscribe := range p.unsubscribe {
+		unsubscribe()
+	}
+	p.loader.unregisterCommands(p, p.commands)
+	p.unsubscribe, p.commands, p.onDisable = nil, nil, nil
+}
+
+// bind sets the globals of the plugin's API in the runtime.
+func (p *plugin) bind() error {
+	vm := p.vm
+	pl := vm.NewObject()
+	events := vm.NewObject()
+	commands := vm.NewObject()
+	prox := vm.NewObject()
+	console := vm.NewObject()
+	for _, err := range []error{
+		pl.Set("name", p.name),
+		pl.Set("onDisable", p.setOnDisable),
+
+		events.Set("on", p.subscribe),
+
+		commands.Set("register", p.registerCommand),
+
+		prox.Set("players", p.loader.proxy.Players),
+		prox.Set("player", p.player),
+		prox.Set("playerCount", p.loader.proxy.PlayerCount),
+		prox.Set("server", p.loader.proxy.Server),
+		prox.Set("servers", p.loader.proxy.Servers),
+		prox.Set("broadcast", p.broadcast),
+
+		console.Set("log", p.logInfo),
+		console.Set("info", p.logInfo),
+		console.Set("warn", p.logInfo),
+		console.Set("error", p.logError),
+
+		vm.Set("plugin", pl),
+		vm.Set("events", events),
+		vm.Set("commands", commands),
+		vm.Set("proxy", prox),
+		vm.Set("console", console),
+		vm.Set("text", text),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setOnDisable sets the function called when the plugin is unloaded.
+func (p *plugin) setOnDisable(fn goja.Callable) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onDisable = fn
+}
+
+// subscribe subscribes the handler fn to the proxy event with the type name.
+func (p *plugin) subscribe(name string, fn goja.Callable, priority int) error {
+	typ, ok := eventTypes[name]
+	if !ok {
+		return fmt.Errorf("unknown event %q", name)
+	}
+	if fn == nil {
+		return errors.New("missing event handler function")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unloaded {
+		return errPluginUnloaded
+	}
+	p.unsubscribe = append(p.unsubscribe, p.loader.proxy.Event().Subscribe(typ, priority, func(e event.Event) {
+		// The handler blocks the event dispatch for up to the eventBusyTimeout
+		// waiting for the plugin and up to the callTimeout running the script.
+		if _, err := p.callWait(eventBusyTimeout, fn, e); err != nil {
+			p.log.Error(err, "error handling event", "event", name)
+		}
+	}))
+	return nil
+}
+
+// registerCommand registers the command handler fn that is called with the command source and arguments.
+// If permission is not empty, the source must have the permission to use the command.
+func (p *plugin) registerCommand(name string, fn goja.Callable, permission string) error {
+	if fn == nil {
+		return errors.New("missing command handler function")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unloaded {
+		return errPluginUnloaded
+	}
+	name = strings.ToLower(name)
+	if err := p.loader.registerCommand(p, name, &scriptCommand{
+		plugin:     p,
+		fn:         fn,
+		permission: permission,
+	}); err != nil {
+		return err
+	}
+	p.commands = append(p.commands, name)
+	return nil
+}
+
+// player returns the online player by name or id, or nil if not found.
+func (p *plugin) player(nameOrID string) proxy.Player {
+	if id, err := uuid.Parse(nameOrID); err == nil {
+		return p.loader.proxy.Player(id)
+	}
+	return p.loader.proxy.PlayerByName(nameOrID)
+}
+
+// broadcast sends the message to all online players.
+func (p *plugin) broadcast(msg goja.Value) error {
+	c, err := toComponent(msg)
+	if err != nil {
+		return err
+	}
+	for _, player := range p.loader.proxy.Players() {
+		_ = player.SendMessage(c)
+	}
+	return nil
+}
+
+func (p *plugin) logInfo(args ...any) {
+	p.log.Info(fmt.Sprint(args...))
+}
+
+func (p *plugin) logError(args ...any) {
+	p.log.Error(nil, fmt.Sprint(args...))
+}
+
+// text returns the text component of s in legacy '§' format or text component json.
+func text(s string) (component.Component, error) {
+	if strings.HasPrefix(s, "{") {
+		return protoutil.LatestJsonCodec().Unmarshal([]byte(s))
+	}
+	return (&legacy.Legacy{}).Unmarshal([]byte(s))
+}
+
+// toComponent returns the component of v or parses v as text if it is no component.
+func toComponent(v goja.Value) (component.Component, error) {
+	if c, ok := v.Export().(component.Component); ok {
+		return c, nil
+	}
+	return text(v.String())
+}
@@ -0,0 +1,303 @@
+// Package script loads plugins written in JavaScript from the .js files of a directory.
+//
+// Every plugin runs isolated in its own embedded JavaScript runtime and is
+// reloaded when its file changes. A plugin uses the following globals:
+//
+//	plugin.name                            // The plugin name, the file name without .js.
+//	plugin.onDisable(fn)                   // Calls fn when the plugin is unloaded.
+//	events.on(name, fn(event), priority)   // Subscribes to a proxy event by type name, e.g. "PlayerChatEvent".
+//	commands.register(name, fn(source, args), permission) // Registers a proxy command.
+//	proxy.players(), proxy.player(nameOrID), proxy.playerCount()
+//	proxy.server(name), proxy.servers()
+//	proxy.broadcast(msg)                   // Sends a message to all players.
+//	text(s)                                // Returns a text component of legacy '§' format or json.
+//	console.log(...), console.error(...)   // Logs with the plugin name.
+//
+// Go values like events, players and servers expose their methods with a lower case
+// first letter, e.g. event.player().sendMessage(text("§aHello!")).
+//
+// Calls into a plugin are serialized and interrupted after a timeout.
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	"go.minekube.com/brigodier"
+	"go.minekube.com/common/minecraft/color"
+	"go.minekube.com/common/minecraft/component"
+	"go.uber.org/multierr"
+
+	"go.minekube.com/gate/pkg/command"
+	"go.minekube.com/gate/pkg/edition/java/proxy"
+)
+
+// ext is the file extension of plugin scripts.
+const ext = ".js"
+
+// reloadDelay is the time to wait for further changes of a
+// plugin file before reloading it, since editors often write files multiple times.
+const reloadDelay = 200 * time.Millisecond
+
+// Options are the options for a new Loader.
+type Options struct {
+	// The proxy the plugins use.
+	Proxy *proxy.Proxy
+	// The directory to load the .js plugin files from.
+	Dir string
+}
+
+// Loader loads the JavaScript plugins of a directory.
+type Loader struct {
+	proxy *proxy.Proxy
+	dir   string
+	log   logr.Logger
+
+	mu      sync.Mutex         // Protects following field and serializes (un)loading
+	plugins map[string]*plugin // loaded plugins by name
+
+	muCmd    sync.RWMutex
+	commands map[string]*scriptCommand // by lower case name, nil if the plugin was unloaded
+}
+
+// New returns a new Loader.
+func New(options Options) (*Loader, error) {
+	if options.Proxy == nil {
+		return nil, errors.New("missing proxy")
+	}
+	if options.Dir == "" {
+		return nil, errors.New("missing plugins directory")
+	}
+	return &Loader{
+		proxy:    options.Proxy,
+		dir:      options.Dir,
+		log:      logr.Discard(), // updated by Loader.Start
+		plugins:  map[string]*plugin{},
+		commands: map[string]*scriptCommand{},
+	}, nil
+}
+
+// Start loads all plugins and reloads the plugins whose files change
+// until ctx is canceled. All plugins are unloaded on return.
+func (l *Loader) Start(ctx context.Context) error {
+	l.log = logr.FromContextOrDiscard(ctx)
+	defer l.unloadAll()
+
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating plugins directory: %w", err)
+	}
+	if err := l.Reload(); err != nil {
+		l.log.Error(err, "error loading plugins")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating plugins directory watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err = watcher.Add(l.dir); err != nil {
+		return fmt.Errorf("error watching plugins directory: %w", err)
+	}
+
+	changed := map[string]bool{}
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if name, ok := pluginName(e.Name); ok {
+				changed[name] = true
+				reload = time.After(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			l.log.Error(err, "error watching plugins directory")
+		case <-reload:
+			for name := range changed {
+				if err := l.load(name); err != nil {
+					l.log.Error(err, "error reloading plugin", "plugin", name)
+				}
+			}
+			changed = map[string]bool{}
+			reload = nil
+		}
+	}
+}
+
+// Reload loads all plugins of the directory, replacing the already
+// loaded ones, and unloads the plugins whose files were removed.
+// A plugin failing to load does not affect the others.
+func (l *Loader) Reload() error {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("error reading plugins directory: %w", err)
+	}
+	names := map[string]bool{}
+	for _, entry := range entries {
+		if name, ok := pluginName(entry.Name()); ok && !entry.IsDir() {
+			names[name] = true
+		}
+	}
+	l.mu.Lock()
+	for name := range l.plugins {
+		names[name] = true // unloaded if removed
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for name := range names {
+		if err = l.load(name); err != nil {
+			errs = append(errs, fmt.Errorf("error loading plugin %q: %w", name, err))
+		}
+	}
+	return multierr.Combine(errs...)
+}
+
+// load (re)loads the plugin from its file or unloads it if the file does not exist.
+func (l *Loader) load(name string) error {
+	src, err := os.ReadFile(filepath.Join(l.dir, name+ext))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if old, ok := l.plugins[name]; ok {
+		old.unload()
+		delete(l.plugins, name)
+		l.log.Info("Unloaded plugin", "plugin", name)
+	}
+	if err != nil {
+		return nil // file was removed
+	}
+
+	p, err := newPlugin(l, name, string(src))
+	if err != nil {
+		return err
+	}
+	l.plugins[name] = p
+	l.log.Info("Loaded plugin", "plugin", name)
+	return nil
+}
+
+func (l *Loader) unloadAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for name, p := range l.plugins {
+		p.unload()
+		delete(l.plugins, name)
+	}
+}
+
+// plugin returns the loaded plugin by name or nil if not loaded.
+func (l *Loader) plugin(name string) *plugin {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.plugins[name]
+}
+
+// pluginName returns the plugin name of a script file path.
+func pluginName(file string) (string, bool) {
+	base := filepath.Base(file)
+	name := strings.TrimSuffix(base, ext)
+	return name, name != base && name != "" && !strings.HasPrefix(name, ".")
+}
+
+// scriptCommand is a command registered by a plugin.
+type scriptCommand struct {
+	plugin     *plugin
+	fn         goja.Callable
+	permission string
+}
+
+// commandArgs is the name of the greedy argument of script commands.
+const commandArgs = "args"
+
+// registerCommand registers the command of the plugin with the proxy's command manager.
+//
+// A command node is only registered once per name and stays registered after the
+// plugin was unloaded, so it can be reused on reload. Unused nodes are hidden
+// from players by their requirement.
+func (l *Loader) registerCommand(p *plugin, name string, cmd *scriptCommand) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("invalid command name %q", name)
+	}
+	l.muCmd.Lock()
+	defer l.muCmd.Unlock()
+	existing, ok := l.commands[name]
+	if !ok {
+		if l.proxy.Command().Has(name) {
+			return fmt.Errorf("command %q is already registered", name)
+		}
+		l.proxy.Command().Register(l.commandNode(name))
+	} else if existing != nil && existing.plugin != p {
+		return fmt.Errorf("command %q is already registered by plugin %q", name, existing.plugin.name)
+	}
+	l.commands[name] = cmd
+	return nil
+}
+
+// unregisterCommands releases the commands of the plugin.
+func (l *Loader) unregisterCommands(p *plugin, names []string) {
+	l.muCmd.Lock()
+	defer l.muCmd.Unlock()
+	for _, name := range names {
+		if cmd := l.commands[name]; cmd != nil && cmd.plugin == p {
+			l.commands[name] = nil
+		}
+	}
+}
+
+// command returns the registered command by name or nil if none.
+func (l *Loader) command(name string) *scriptCommand {
+	l.muCmd.RLock()
+	defer l.muCmd.RUnlock()
+	return l.commands[name]
+}
+
+func (l *Loader) commandNode(name string) brigodier.LiteralNodeBuilder {
+	return brigodier.Literal(name).
+		Requires(command.Requires(func(c *command.RequiresContext) bool {
+			cmd := l.command(name)
+			return cmd != nil && (cmd.permission == "" || c.Source.HasPermission(cmd.permission))
+		})).
+		Executes(command.Command(func(c *command.Context) error {
+			return l.executeCommand(name, c.Source, []string{})
+		})).
+		Then(brigodier.Argument(commandArgs, brigodier.GreedyPhrase).
+			Executes(command.Command(func(c *command.Context) error {
+				return l.executeCommand(name, c.Source, strings.Fields(c.String(commandArgs)))
+			})),
+		)
+}
+
+func (l *Loader) executeCommand(name string, src command.Source, args []string) error {
+	cmd := l.command(name)
+	if cmd == nil {
+		return nil // plugin was unloaded in the meantime
+	}
+	if _, err := cmd.plugin.call(cmd.fn, src, args); err != nil {
+		cmd.plugin.log.Error(err, "error executing command", "command", name)
+		return src.SendMessage(&component.Text{
+			Content: "An error occurred while executing this command.",
+			S:       component.Style{Color: color.Red},
+		})
+	}
+	return nil
+}
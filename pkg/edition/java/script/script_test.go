@@ -0,0 +1,129 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/common/minecraft/component"
+
+	"go.minekube.com/gate/pkg/command"
+	"go.minekube.com/gate/pkg/edition/java/config"
+	"go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/runtime/event"
+	"go.minekube.com/gate/pkg/util/permission"
+)
+
+func newLoader(t *testing.T, scripts map[string]string) *Loader {
+	cfg := config.DefaultConfig
+	p, err := proxy.New(proxy.Options{Config: &cfg, EventMgr: event.New(logr.Discard())})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	for name, src := range scripts {
+		writeScript(t, dir, name, src)
+	}
+	l, err := New(Options{Proxy: p, Dir: dir})
+	require.NoError(t, err)
+	t.Cleanup(l.unloadAll)
+	return l
+}
+
+func writeScript(t *testing.T, dir, name, src string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+ext), []byte(src), 0o644))
+}
+
+func global(l *Loader, plugin, name string) any {
+	return l.plugin(plugin).vm.Get(name).Export()
+}
+
+func TestLoader_Events(t *testing.T) {
+	l := newLoader(t, map[string]string{
+		"a":   `let ready = 0; events.on("ReadyEvent", () => { ready++ })`,
+		"bad": `events.on("NoSuchEvent", () => {})`,
+	})
+	require.Error(t, l.Reload())
+	require.Nil(t, l.plugin("bad"))
+
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	require.EqualValues(t, 1, global(l, "a", "ready"))
+
+	// Reload unsubscribes the handlers of the old plugin
+	old := l.plugin("a")
+	writeScript(t, l.dir, "a", `let ready = 10; events.on("ReadyEvent", () => { ready++ })`)
+	require.NoError(t, l.load("a"))
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	require.EqualValues(t, 11, global(l, "a", "ready"))
+	require.EqualValues(t, 1, old.vm.Get("ready").Export())
+}
+
+type testSource struct{ msgs []string }
+
+func (s *testSource) HasPermission(string) bool { return true }
+func (s *testSource) PermissionValue(string) permission.TriState {
+	return permission.True
+}
+func (s *testSource) SendMessage(msg component.Component, _ ...command.MessageOption) error {
+	s.msgs = append(s.msgs, msg.(*component.Text).Content)
+	return nil
+}
+
+func TestLoader_Commands(t *testing.T) {
+	l := newLoader(t, map[string]string{
+		"a": `commands.register("hello", (source, args) => {
+			source.sendMessage(text("hi " + args.join(",")))
+		})`,
+	})
+	require.NoError(t, l.Reload())
+
+	src := &testSource{}
+	mgr := l.proxy.Command()
+	require.NoError(t, mgr.Do(context.TODO(), src, "hello a b"))
+	require.NoError(t, mgr.Do(context.TODO(), src, "hello"))
+	require.Equal(t, []string{"hi a,b", "hi "}, src.msgs)
+
+	// Another plugin can not take over the command
+	writeScript(t, l.dir, "b", `commands.register("hello", () => {})`)
+	require.Error(t, l.load("b"))
+
+	// Removing the plugin unregisters its command
+	require.NoError(t, os.Remove(filepath.Join(l.dir, "a"+ext)))
+	require.NoError(t, l.Reload())
+	require.Nil(t, l.plugin("a"))
+	require.Error(t, mgr.Do(context.TODO(), src, "hello"))
+	require.NoError(t, l.load("b"))
+}
+
+func TestLoader_Timeout(t *testing.T) {
+	defer func(d time.Duration) { callTimeout = d }(callTimeout)
+	callTimeout = 50 * time.Millisecond
+
+	l := newLoader(t, map[string]string{
+		"a": `let calls = 0; events.on("ReadyEvent", () => { calls++; for (;;) {} })`,
+	})
+	require.NoError(t, l.Reload())
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	require.EqualValues(t, 2, global(l, "a", "calls"))
+}
+
+func TestLoader_EventBusy(t *testing.T) {
+	l := newLoader(t, map[string]string{
+		"a": `let calls = 0; events.on("ReadyEvent", () => { calls++ })`,
+	})
+	require.NoError(t, l.Reload())
+	p := l.plugin("a")
+
+	p.sem <- struct{}{} // simulate a long-running call into the plugin
+	start := time.Now()
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	require.Less(t, time.Since(start), callTimeout)
+	<-p.sem
+	require.EqualValues(t, 0, global(l, "a", "calls"))
+
+	l.proxy.Event().Fire(&proxy.ReadyEvent{})
+	require.EqualValues(t, 1, global(l, "a", "calls"))
+}
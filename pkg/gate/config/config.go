@@ -25,6 +25,14 @@ var DefaultConfig = Config{
 		Enabled: false,
 		Bind:    "0.0.0.0:9090",
 	},
+	Metrics: Metrics{
+		Enabled: false,
+		Bind:    "0.0.0.0:9091",
+	},
+	Plugins: Plugins{
+		Enabled: false,
+		Dir:     "plugins",
+	},
 	Connect: connect.DefaultConfig,
 }
 
@@ -34,6 +42,10 @@ type Config struct {
 	Editions Editions
 	// See HealthService struct.
 	HealthService HealthService
+	// See Metrics struct.
+	Metrics Metrics
+	// See Plugins struct.
+	Plugins Plugins
 	// See Connect struct.
 	Connect connect.Config
 }
@@ -65,6 +77,19 @@ type HealthService struct {
 	Bind    string
 }
 
+// Metrics is an HTTP endpoint serving metrics of Gate in the Prometheus format.
+type Metrics struct {
+	Enabled bool
+	Bind    string
+}
+
+// Plugins loads JavaScript plugins from the .js files of a directory
+// and reloads them on changes. They use the Java edition proxy.
+type Plugins struct {
+	Enabled bool
+	Dir     string
+}
+
 // Validate validates a Config and all enabled edition configs (Java / Bedrock).
 func (c *Config) Validate() (warns []error, errs []error) {
 	e := func(m string, args ...any) { errs = append(errs, fmt.Errorf(m, args...)) }
@@ -78,6 +103,19 @@ func (c *Config) Validate() (warns []error, errs []error) {
 			e("Invalid health probe bind address %q: %v", c.HealthService.Bind, err)
 		}
 	}
+	if c.Metrics.Enabled {
+		if err := validation.ValidHostPort(c.Metrics.Bind); err != nil {
+			e("Invalid metrics bind address %q: %v", c.Metrics.Bind, err)
+		}
+	}
+	if c.Plugins.Enabled {
+		if c.Plugins.Dir == "" {
+			e("Plugins directory is empty")
+		}
+		if !c.Editions.Java.Enabled {
+			e("Plugins require the java edition to be enabled")
+		}
+	}
 
 	prefix := func(p string, errs []error) (pErrs []error) {
 		for _, err := range errs {
@@ -93,7 +131,7 @@ func (c *Config) Validate() (warns []error, errs []error) {
 		errs = append(errs, prefix("java", errs2)...)
 	}
 	if c.Editions.Bedrock.Enabled {
-		warns2, errs2 := c.Editions.Java.Config.Validate()
+		warns2, errs2 := c.Editions.Bedrock.Config.Validate()
 		warns = append(warns, prefix("bedrock", warns2)...)
 		errs = append(errs, prefix("bedrock", errs2)...)
 	}
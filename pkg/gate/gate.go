@@ -4,11 +4,17 @@ package gate
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
 	"syscall"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-logr/logr"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"go.uber.org/multierr"
 
@@ -16,7 +22,9 @@ import (
 	"go.minekube.com/gate/pkg/edition"
 	bproxy "go.minekube.com/gate/pkg/edition/bedrock/proxy"
 	jproxy "go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/edition/java/script"
 	"go.minekube.com/gate/pkg/gate/config"
+	"go.minekube.com/gate/pkg/internal/console"
 	"go.minekube.com/gate/pkg/runtime/event"
 	"go.minekube.com/gate/pkg/runtime/process"
 	connectcfg "go.minekube.com/gate/pkg/util/connectutil/config"
@@ -28,7 +36,8 @@ type Options struct {
 	// Config requires a valid Gate configuration.
 	Config *config.Config
 	// The event manager to use.
-	// If none is set, no events are sent.
+	// If none is set, no events are sent
+	// unless required by the enabled health service.
 	EventMgr event.Manager
 }
 
@@ -46,9 +55,15 @@ func New(options Options) (gate *Gate, err error) {
 			"(errors: %d, warns: %d)", len(errs), len(warns))
 	}
 
+	c := options.Config
 	eventMgr := options.EventMgr
 	if eventMgr == nil {
-		eventMgr = event.Nop
+		if c.HealthService.Enabled {
+			// The health service depends on proxy events
+			eventMgr = event.New(logr.Discard())
+		} else {
+			eventMgr = event.Nop
+		}
 	}
 
 	gate = &Gate{
@@ -56,7 +71,6 @@ func New(options Options) (gate *Gate, err error) {
 		bridge: &bridge.Bridge{},
 	}
 
-	c := options.Config
 	if c.Editions.Java.Enabled {
 		gate.bridge.JavaProxy, err = jproxy.New(jproxy.Options{
 			Config:   &c.Editions.Java.Config,
@@ -111,6 +125,42 @@ func New(options Options) (gate *Gate, err error) {
 		}
 	}
 
+	if c.HealthService.Enabled {
+		health := newHealthService(c.HealthService.Bind, eventMgr, c.Editions.Java.Enabled)
+		if err = gate.proc.Add(process.RunnableFunc(func(ctx context.Context) error {
+			ctx = logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithName("health"))
+			return health.Start(ctx)
+		})); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Plugins.Enabled && c.Editions.Java.Enabled {
+		loader, err := script.New(script.Options{
+			Proxy: gate.Java(),
+			Dir:   c.Plugins.Dir,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error setting up plugins: %w", err)
+		}
+		if err = gate.proc.Add(process.RunnableFunc(func(ctx context.Context) error {
+			ctx = logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithName("plugins"))
+			return loader.Start(ctx)
+		})); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Metrics.Enabled {
+		metrics := newMetricsService(c.Metrics.Bind)
+		if err = gate.proc.Add(process.RunnableFunc(func(ctx context.Context) error {
+			ctx = logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithName("metrics"))
+			return metrics.Start(ctx)
+		})); err != nil {
+			return nil, err
+		}
+	}
+
 	return gate, nil
 }
 
@@ -133,13 +183,28 @@ func (g *Gate) Bedrock() *bproxy.Proxy {
 // Start starts the Gate instance and all underlying proc.
 func (g *Gate) Start(ctx context.Context) error { return g.proc.Start(ctx) }
 
+// Reload applies the config to the running Gate instance.
+// Currently, only the Java edition config can be reloaded, see jproxy.Proxy.Reload.
+func (g *Gate) Reload(c *config.Config) error {
+	if c == nil {
+		return errors.ErrMissingConfig
+	}
+	if g.Java() == nil || !c.Editions.Java.Enabled {
+		return fmt.Errorf("%s edition must be enabled to reload the config", edition.Java)
+	}
+	return g.Java().Reload(&c.Editions.Java.Config)
+}
+
 // Viper is the default viper instance used by Start to load in a config.Config.
 var Viper = viper.New()
 
 type StartOption func(o *startOptions)
 
 type startOptions struct {
-	Config *config.Config
+	Config     *config.Config
+	AutoReload *viper.Viper
+	ConsoleIn  io.Reader
+	ConsoleOut io.Writer
 }
 
 // LoadConfig loads in config.Config from viper.
@@ -148,12 +213,53 @@ func LoadConfig(v *viper.Viper) (*config.Config, error) {
 	// Clone default config
 	cfg := func() config.Config { return config.DefaultConfig }()
 	// Load in Gate config
-	if err := v.Unmarshal(&cfg); err != nil {
+	settings := v.AllSettings()
+	for _, path := range hostKeyedSettings {
+		// Viper splits keys at dots, so take the maps keyed by hosts as read in.
+		if raw := v.Get(strings.Join(path, ".")); raw != nil {
+			setSetting(settings, path, raw)
+		}
+	}
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &cfg,
+		WeaklyTypedInput: true, // like viper.Unmarshal
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			// Viper's defaults
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			// Allows servers to be given as plain address strings
+			mapstructure.TextUnmarshallerHookFunc(),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = dec.Decode(settings); err != nil {
 		return nil, fmt.Errorf("error loading config: %w", err)
 	}
 	return &cfg, nil
 }
 
+// hostKeyedSettings are the paths of the config maps whose keys are
+// host names that usually contain dots, e.g. "lobby.example.com".
+var hostKeyedSettings = [][]string{
+	{"editions", "java", "config", "forcedhosts"},
+	{"editions", "java", "config", "status", "forcedhostmotds"},
+}
+
+// setSetting sets the value at the path of nested settings maps.
+func setSetting(settings map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := settings[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			settings[key] = next
+		}
+		settings = next
+	}
+	settings[path[len(path)-1]] = value
+}
+
 // WithConfig StartOption for Start.
 func WithConfig(c config.Config) StartOption {
 	return func(o *startOptions) {
@@ -161,6 +267,25 @@ func WithConfig(c config.Config) StartOption {
 	}
 }
 
+// WithAutoConfigReload StartOption for Start watches the config file
+// read in by v and reloads the config using Gate.Reload when it changes.
+func WithAutoConfigReload(v *viper.Viper) StartOption {
+	return func(o *startOptions) {
+		o.AutoReload = v
+	}
+}
+
+// WithConsole StartOption for Start reads command lines from in,
+// e.g. os.Stdin, and executes them as console with all permissions
+// using the Java edition's command manager. Replies are written to out.
+// If in is a terminal, it provides line editing with tab completion and history.
+func WithConsole(in io.Reader, out io.Writer) StartOption {
+	return func(o *startOptions) {
+		o.ConsoleIn = in
+		o.ConsoleOut = out
+	}
+}
+
 // Start is a convenience function to set up and run a Gate instance.
 //
 // It sets up a Logger, reads in a Config, validates it and sets up
@@ -207,6 +332,9 @@ func Start(ctx context.Context, opts ...StartOption) error {
 	if err != nil {
 		return fmt.Errorf("error creating Gate instance: %w", err)
 	}
+	if c.AutoReload != nil {
+		watchConfig(c.AutoReload, c.Config, gate, configLog)
+	}
 
 	// Setup os signal channel to trigger Gate shutdown.
 	sig := make(chan os.Signal, 1)
@@ -228,6 +356,54 @@ func Start(ctx context.Context, opts ...StartOption) error {
 		}
 	}()
 
+	if c.ConsoleIn != nil && gate.Java() != nil {
+		con := &console.Console{
+			Manager: gate.Java().Command(),
+			In:      c.ConsoleIn,
+			Out:     c.ConsoleOut,
+			Log:     log.WithName("console"),
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if err := con.Run(ctx); err != nil {
+				con.Log.Error(err, "error reading console input")
+			}
+		}()
+		// Wait for the console to restore the terminal before returning
+		defer func() { cancel(); <-done }()
+	}
+
 	// Start everything
 	return gate.Start(ctx)
 }
+
+// watchConfig reloads the config of the Gate instance
+// whenever the config file read in by v changes.
+func watchConfig(v *viper.Viper, current *config.Config, gate *Gate, log logr.Logger) {
+	var mu sync.Mutex // file changes are usually reported multiple times at once
+	v.OnConfigChange(func(e fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		log.Info("config file changed, reloading", "file", e.Name)
+		cfg, err := LoadConfig(v)
+		if err != nil {
+			log.Error(err, "could not reload config")
+			return
+		}
+		if err = gate.Reload(cfg); err != nil {
+			log.Error(err, "could not reload config")
+			return
+		}
+		if !reflect.DeepEqual(current.Editions.Bedrock, cfg.Editions.Bedrock) ||
+			current.Editions.Java.Enabled != cfg.Editions.Java.Enabled ||
+			current.HealthService != cfg.HealthService ||
+			current.Metrics != cfg.Metrics ||
+			current.Plugins != cfg.Plugins ||
+			!reflect.DeepEqual(current.Connect, cfg.Connect) {
+			log.Info("Changes to settings other than the java edition config require a restart to take effect")
+		}
+		current = cfg
+	})
+	v.WatchConfig()
+}
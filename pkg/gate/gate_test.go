@@ -0,0 +1,40 @@
+package gate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_hostKeys(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(`
+editions:
+  java:
+    config:
+      bind: 0.0.0.0:25566
+      forcedHosts:
+        "*": [lobby]
+        "*.example.com": [wild]
+        lobby.example.com: [lobby, fallback]
+      status:
+        motd: default
+        forcedHostMotds:
+          lobby.example.com: lobby
+`)))
+
+	cfg, err := LoadConfig(v)
+	require.NoError(t, err)
+	java := cfg.Editions.Java.Config
+	require.Equal(t, "0.0.0.0:25566", java.Bind)
+	require.Equal(t, "default", java.Status.Motd)
+	require.Equal(t, map[string][]string{
+		"*":                 {"lobby"},
+		"*.example.com":     {"wild"},
+		"lobby.example.com": {"lobby", "fallback"},
+	}, map[string][]string(java.ForcedHosts))
+	require.Equal(t, map[string]string{"lobby.example.com": "lobby"}, java.Status.ForcedHostMotds)
+}
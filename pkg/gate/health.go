@@ -0,0 +1,62 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	jproxy "go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/runtime/event"
+	"go.minekube.com/gate/pkg/runtime/process"
+)
+
+// newHealthService returns a Runnable serving the standard grpc.health.v1.Health
+// service on bind address for use as Kubernetes readiness and liveness probe.
+//
+// If waitReady is true, the service reports NOT_SERVING until the java proxy
+// fired the jproxy.ReadyEvent, otherwise it reports SERVING right away.
+// In both cases it reports NOT_SERVING once the jproxy.PreShutdownEvent is fired.
+func newHealthService(bind string, eventMgr event.Manager, waitReady bool) process.Runnable {
+	srv := health.NewServer()
+	if waitReady {
+		srv.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		event.Subscribe(eventMgr, 0, func(*jproxy.ReadyEvent) {
+			srv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		})
+	}
+	event.Subscribe(eventMgr, 0, func(*jproxy.PreShutdownEvent) {
+		// Stop serving while players are disconnected
+		srv.Shutdown()
+	})
+
+	return process.RunnableFunc(func(ctx context.Context) error {
+		log := logr.FromContextOrDiscard(ctx)
+
+		ln, err := net.Listen("tcp", bind)
+		if err != nil {
+			return fmt.Errorf("error listening for health probes: %w", err)
+		}
+
+		svr := grpc.NewServer()
+		healthpb.RegisterHealthServer(svr, srv)
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- svr.Serve(ln) }()
+		log.Info("Serving health probes", "addr", ln.Addr().String())
+
+		select {
+		case <-ctx.Done():
+			srv.Shutdown()
+			// Don't wait for watching clients to disconnect
+			svr.Stop()
+			return nil
+		case err = <-errCh:
+			return fmt.Errorf("error serving health probes: %w", err)
+		}
+	})
+}
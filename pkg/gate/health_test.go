@@ -0,0 +1,77 @@
+package gate
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	jproxy "go.minekube.com/gate/pkg/edition/java/proxy"
+	"go.minekube.com/gate/pkg/runtime/event"
+)
+
+func TestHealthService(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	bind := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	eventMgr := event.New(logr.Discard())
+	svc := newHealthService(bind, eventMgr, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- svc.Start(ctx) }()
+
+	conn, err := grpc.Dial(bind, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+		return res.GetStatus()
+	}
+
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status())
+	eventMgr.Fire(&jproxy.ReadyEvent{})
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status())
+	eventMgr.Fire(&jproxy.PreShutdownEvent{})
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status())
+
+	cancel()
+	select {
+	case err = <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("health service did not stop")
+	}
+}
+
+func TestHealthService_noWaitReady(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	bind := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = newHealthService(bind, event.Nop, false).Start(ctx) }()
+
+	conn, err := grpc.Dial(bind, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	checkCtx, checkCancel := context.WithTimeout(ctx, time.Second)
+	defer checkCancel()
+	res, err := healthpb.NewHealthClient(conn).Check(checkCtx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+}
@@ -0,0 +1,52 @@
+package gate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"go.minekube.com/gate/pkg/runtime/process"
+)
+
+// newMetricsService returns a Runnable serving the metrics
+// of Gate in the Prometheus format at /metrics on bind address.
+func newMetricsService(bind string) process.Runnable {
+	return process.RunnableFunc(func(ctx context.Context) error {
+		log := logr.FromContextOrDiscard(ctx)
+
+		ln, err := net.Listen("tcp", bind)
+		if err != nil {
+			return fmt.Errorf("error listening for metrics: %w", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		svr := &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- svr.Serve(ln) }()
+		log.Info("Serving metrics", "addr", ln.Addr().String())
+
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = svr.Shutdown(shutdownCtx)
+			return nil
+		case err = <-errCh:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return fmt.Errorf("error serving metrics: %w", err)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"go.minekube.com/brigodier"
+	"go.minekube.com/common/minecraft/component"
+	"go.minekube.com/common/minecraft/component/codec/legacy"
+	"golang.org/x/term"
+
+	"go.minekube.com/gate/pkg/command"
+	"go.minekube.com/gate/pkg/util/permission"
+)
+
+// prompt is the prompt of the console in a terminal.
+const prompt = "> "
+
+// Console reads command lines from an input, e.g. the terminal,
+// and executes them through a command.Manager as Source that has all permissions.
+//
+// If the input is a terminal, the console puts it into raw mode to provide
+// a line editor with tab completion of commands and a history that is
+// browsed with the arrow keys. Other inputs are simply read line by line.
+type Console struct {
+	Manager *command.Manager
+	In      io.Reader
+	Out     io.Writer
+	Log     logr.Logger
+
+	mu  sync.Mutex // Protects following field and writes to the output
+	out io.Writer  // The terminal while reading from one, nil to use Out
+}
+
+var _ command.Source = (*Console)(nil)
+
+// Run reads and executes command lines until the input is closed or ctx is canceled.
+func (c *Console) Run(ctx context.Context) error {
+	if f, ok := c.In.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return c.runTerminal(ctx, f)
+	}
+	scanner := bufio.NewScanner(c.In)
+	return c.readLines(ctx, func() (string, error) {
+		if scanner.Scan() {
+			return scanner.Text(), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	})
+}
+
+// runTerminal reads command lines from the terminal in raw mode.
+func (c *Console) runTerminal(ctx context.Context, in *os.File) error {
+	fd := int(in.Fd())
+	state, err := makeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("error putting terminal into raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, c.Out}, prompt)
+	if width, height, err := term.GetSize(fd); err == nil {
+		_ = t.SetSize(width, height)
+	}
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return c.complete(ctx, line, pos)
+	}
+
+	c.setOut(t)
+	defer c.setOut(nil)
+	return c.readLines(ctx, t.ReadLine)
+}
+
+// readLines handles the lines returned by readLine until it fails or ctx is canceled.
+// Since reading can not be canceled, readLine is called in another goroutine.
+func (c *Console) readLines(ctx context.Context, readLine func() (string, error)) error {
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := readLine()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-readErr; err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+				return nil
+			}
+			c.handleLine(ctx, line)
+		}
+	}
+}
+
+func (c *Console) handleLine(ctx context.Context, line string) {
+	line = strings.TrimLeft(strings.TrimSpace(line), "/")
+	if line == "" {
+		return
+	}
+	c.Log.Info("console executing command", "command", line)
+	err := c.Manager.Do(ctx, c, line)
+	if err == nil {
+		return
+	}
+	if errors.Is(err, brigodier.ErrDispatcherUnknownCommand) {
+		c.println(AnsiFromLegacy("§cUnknown command."))
+		return
+	}
+	var sErr *brigodier.CommandSyntaxError
+	if errors.As(err, &sErr) {
+		c.println(AnsiFromLegacy("§c" + sErr.Error()))
+		return
+	}
+	c.Log.Error(err, "error executing console command", "command", line)
+}
+
+// complete completes the word before pos in line with the command suggestions.
+// If there are multiple suggestions, the word is completed to their common
+// prefix and the suggestions are printed if it can not be completed further.
+func (c *Console) complete(ctx context.Context, line string, pos int) (string, int, bool) {
+	before := line[:pos]
+	cmdline := strings.TrimLeft(before, "/")
+	suggestions, err := c.Manager.OfferSuggestions(ctx, c, cmdline)
+	if err != nil {
+		c.Log.Error(err, "error getting command suggestions", "command", cmdline)
+		return "", 0, false
+	}
+	if len(suggestions) == 0 {
+		return "", 0, false
+	}
+	start := len(before) - len(cmdline) + strings.LastIndexByte(cmdline, ' ') + 1
+	word := before[start:]
+
+	completion := suggestions[0]
+	if len(suggestions) == 1 {
+		completion += " "
+	} else {
+		for _, s := range suggestions[1:] {
+			completion = commonPrefix(completion, s)
+		}
+		if len(completion) <= len(word) {
+			sort.Strings(suggestions)
+			c.println(strings.Join(suggestions, "  "))
+			return "", 0, false
+		}
+	}
+	if !strings.HasPrefix(completion, word) {
+		return "", 0, false
+	}
+	return before[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// commonPrefix returns the longest common prefix of a and b.
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
+}
+
+func (c *Console) setOut(out io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out = out
+}
+
+func (c *Console) println(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := c.out
+	if out == nil {
+		out = c.Out
+	}
+	_, _ = fmt.Fprintln(out, s)
+}
+
+// SendMessage prints the message colored to the output.
+func (c *Console) SendMessage(msg component.Component, _ ...command.MessageOption) error {
+	b := new(strings.Builder)
+	if err := (&legacy.Legacy{}).Marshal(b, msg); err != nil {
+		return err
+	}
+	c.println(AnsiFromLegacy(b.String()))
+	return nil
+}
+
+// HasPermission always returns true since the console has all permissions.
+func (c *Console) HasPermission(string) bool { return true }
+
+// PermissionValue always returns permission.True since the console has all permissions.
+func (c *Console) PermissionValue(string) permission.TriState { return permission.True }
@@ -0,0 +1,72 @@
+package console
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"go.minekube.com/brigodier"
+
+	"go.minekube.com/gate/pkg/command"
+)
+
+func newTestConsole(in string, executed *[]string) (*Console, *strings.Builder) {
+	mgr := new(command.Manager)
+	record := func(line string) brigodier.Command {
+		return command.Command(func(c *command.Context) error {
+			*executed = append(*executed, line)
+			return nil
+		})
+	}
+	mgr.Register(brigodier.Literal("glist").Executes(record("glist")).
+		Then(brigodier.Literal("all").Executes(record("glist all"))))
+	mgr.Register(brigodier.Literal("gate").Executes(record("gate")))
+	mgr.Register(brigodier.Literal("send").
+		Then(brigodier.Literal("alice").Executes(record("send alice"))).
+		Then(brigodier.Literal("all").Executes(record("send all"))))
+	out := new(strings.Builder)
+	return &Console{
+		Manager: mgr,
+		In:      strings.NewReader(in),
+		Out:     out,
+		Log:     logr.Discard(),
+	}, out
+}
+
+func TestConsole_Run(t *testing.T) {
+	var executed []string
+	c, out := newTestConsole("glist\n  /glist all \n\n/\nunknown\n", &executed)
+	require.NoError(t, c.Run(context.Background()))
+	require.Equal(t, []string{"glist", "glist all"}, executed)
+	require.Contains(t, out.String(), "Unknown command.")
+}
+
+func TestConsole_complete(t *testing.T) {
+	var executed []string
+	c, out := newTestConsole("", &executed)
+	tests := []struct {
+		line    string
+		pos     int
+		newLine string
+		newPos  int
+		ok      bool
+	}{
+		{line: "gl", pos: 2, newLine: "glist ", newPos: 6, ok: true},
+		{line: "/gl", pos: 3, newLine: "/glist ", newPos: 7, ok: true},
+		{line: "glx", pos: 2, newLine: "glist x", newPos: 6, ok: true},
+		{line: "glist a", pos: 7, newLine: "glist all ", newPos: 10, ok: true},
+		{line: "send a", pos: 6, newLine: "send al", newPos: 7, ok: true},
+		{line: "send al", pos: 7},  // prints alice and all
+		{line: "unknown", pos: 7},  // no suggestions
+		{line: "send bob", pos: 8}, // no suggestions
+	}
+	for _, tt := range tests {
+		newLine, newPos, ok := c.complete(context.Background(), tt.line, tt.pos)
+		require.Equal(t, tt.ok, ok, tt.line)
+		require.Equal(t, tt.newLine, newLine, tt.line)
+		require.Equal(t, tt.newPos, newPos, tt.line)
+	}
+	require.Equal(t, "alice  all\n", out.String())
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package console
+
+import "golang.org/x/term"
+
+// makeRaw puts the terminal into raw mode.
+func makeRaw(fd int) (*term.State, error) {
+	return term.MakeRaw(fd)
+}
@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package console
+
+import (
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// makeRaw puts the terminal into raw mode like term.MakeRaw, but keeps the
+// translation of "\n" to "\r\n" so that logs written directly to the terminal
+// stay readable, and the signal generation so that Ctrl+C still stops Gate.
+func makeRaw(fd int) (*term.State, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	termios, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err == nil {
+		termios.Oflag |= unix.OPOST | unix.ONLCR
+		termios.Lflag |= unix.ISIG
+		err = unix.IoctlSetTermios(fd, ioctlWriteTermios, termios)
+	}
+	if err != nil {
+		_ = term.Restore(fd, state)
+		return nil, err
+	}
+	return state, nil
+}
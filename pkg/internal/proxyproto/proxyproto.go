@@ -0,0 +1,325 @@
+// Package proxyproto implements the HAProxy PROXY protocol version 1 and 2
+// used by load balancers to pass on the original client address.
+//
+// See https://www.haproxy.org/download/2.6/doc/proxy-protocol.txt
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// v1Prefix is the prefix of the human-readable version 1 header.
+	v1Prefix = []byte("PROXY ")
+	// v2Signature is the signature of the binary version 2 header.
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// v1MaxLength is the maximum length of a version 1 header including the CRLF.
+const v1MaxLength = 107
+
+// Version 2 commands and address families.
+const (
+	v2VersionLocal byte = 0x20
+	v2VersionProxy byte = 0x21
+
+	v2FamilyUnspec byte = 0x00
+	v2FamilyTCP4   byte = 0x11
+	v2FamilyUDP4   byte = 0x12
+	v2FamilyTCP6   byte = 0x21
+	v2FamilyUDP6   byte = 0x22
+)
+
+// v2MaxLength is the maximum length of an address block written by Header.Format.
+const v2MaxLength = 2*net.IPv6len + 4
+
+var (
+	// ErrNoHeader is returned by Read if the stream does not start with a PROXY protocol header.
+	ErrNoHeader = errors.New("proxyproto: no PROXY protocol header")
+	// ErrInvalidHeader is returned by Read if the PROXY protocol header is malformed.
+	ErrInvalidHeader = errors.New("proxyproto: invalid PROXY protocol header")
+)
+
+// Header is a PROXY protocol header.
+type Header struct {
+	// Version is the protocol version of the header, either 1 or 2.
+	Version byte
+	// Local is true if the connection was established by the
+	// upstream proxy itself (e.g. for health checks) and the
+	// addresses must be ignored.
+	// It is set for the version 2 LOCAL command and the version 1 UNKNOWN protocol.
+	Local bool
+	// Source is the address of the original client.
+	Source *net.TCPAddr
+	// Destination is the address the original client connected to.
+	Destination *net.TCPAddr
+}
+
+// Read reads a version 1 or 2 PROXY protocol header from r.
+// ErrNoHeader is returned if r does not start with a header.
+func Read(r *bufio.Reader) (*Header, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	switch b[0] {
+	case v1Prefix[0]:
+		return readV1(r)
+	case v2Signature[0]:
+		return readV2(r)
+	default:
+		return nil, ErrNoHeader
+	}
+}
+
+func readV1(r *bufio.Reader) (*Header, error) {
+	b, err := r.Peek(len(v1Prefix))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(b, v1Prefix) {
+		return nil, ErrNoHeader
+	}
+
+	var line []byte
+	for len(line) < v1MaxLength {
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, c)
+		if c == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, fmt.Errorf("%w: missing CRLF", ErrInvalidHeader)
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("%w: missing protocol", ErrInvalidHeader)
+	}
+	h := &Header{Version: 1}
+	switch fields[1] {
+	case "UNKNOWN":
+		// The receiver must ignore anything after UNKNOWN
+		h.Local = true
+		return h, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, fmt.Errorf("%w: unknown protocol %q", ErrInvalidHeader, fields[1])
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("%w: expected 6 fields, got %d", ErrInvalidHeader, len(fields))
+	}
+	if h.Source, err = parseV1Addr(fields[2], fields[4]); err != nil {
+		return nil, err
+	}
+	if h.Destination, err = parseV1Addr(fields[3], fields[5]); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func parseV1Addr(ip, port string) (*net.TCPAddr, error) {
+	addr := &net.TCPAddr{IP: net.ParseIP(ip)}
+	if addr.IP == nil {
+		return nil, fmt.Errorf("%w: invalid ip %q", ErrInvalidHeader, ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid port %q", ErrInvalidHeader, port)
+	}
+	addr.Port = int(p)
+	return addr, nil
+}
+
+func readV2(r *bufio.Reader) (*Header, error) {
+	b, err := r.Peek(len(v2Signature) + 4)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(b[:len(v2Signature)], v2Signature) {
+		return nil, ErrNoHeader
+	}
+	b = b[len(v2Signature):]
+	verCmd, family := b[0], b[1]
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if _, err = r.Discard(len(v2Signature) + 4); err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	h := &Header{Version: 2}
+	switch verCmd {
+	case v2VersionLocal:
+		// The receiver must ignore the address information
+		h.Local = true
+		return h, nil
+	case v2VersionProxy:
+	default:
+		return nil, fmt.Errorf("%w: unknown version and command 0x%02x", ErrInvalidHeader, verCmd)
+	}
+
+	var ipLen int
+	switch family {
+	case v2FamilyTCP4, v2FamilyUDP4:
+		ipLen = net.IPv4len
+	case v2FamilyTCP6, v2FamilyUDP6:
+		ipLen = net.IPv6len
+	default:
+		// Unspecified or unix socket addresses can't be represented,
+		// the receiver must accept the connection using the real addresses.
+		h.Local = true
+		return h, nil
+	}
+	if len(payload) < 2*ipLen+4 {
+		return nil, fmt.Errorf("%w: address block too short", ErrInvalidHeader)
+	}
+	h.Source = &net.TCPAddr{
+		IP:   net.IP(payload[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen:])),
+	}
+	h.Destination = &net.TCPAddr{
+		IP:   net.IP(payload[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen+2:])),
+	}
+	// Any remaining bytes are TLVs we don't need and have already been discarded.
+	return h, nil
+}
+
+// NewHeader returns a version 2 header for a connection from src to dst.
+// If an address is no tcp address with an ip, the header is a LOCAL
+// header telling the receiver to use the real connection addresses.
+func NewHeader(src, dst net.Addr) *Header {
+	h := &Header{Version: 2, Source: tcpAddr(src), Destination: tcpAddr(dst)}
+	h.Local = h.Source == nil || h.Destination == nil
+	return h
+}
+
+func tcpAddr(addr net.Addr) *net.TCPAddr {
+	if addr == nil {
+		return nil
+	}
+	if a, ok := addr.(*net.TCPAddr); ok {
+		return a
+	}
+	a, err := net.ResolveTCPAddr("tcp", addr.String())
+	if err != nil || a.IP == nil {
+		return nil
+	}
+	return a
+}
+
+// Format encodes the header in the format of its Version.
+func (h *Header) Format() ([]byte, error) {
+	switch h.Version {
+	case 1:
+		return h.formatV1(), nil
+	case 2:
+		return h.formatV2(), nil
+	default:
+		return nil, fmt.Errorf("proxyproto: unsupported version %d", h.Version)
+	}
+}
+
+func (h *Header) formatV1() []byte {
+	if h.Local {
+		return []byte("PROXY UNKNOWN\r\n")
+	}
+	proto := "TCP4"
+	src, dst := h.Source.IP.To4(), h.Destination.IP.To4()
+	if src == nil || dst == nil {
+		proto = "TCP6"
+		src, dst = h.Source.IP.To16(), h.Destination.IP.To16()
+	}
+	return []byte(fmt.Sprintf("PROXY %s %s %s %d %d\r\n",
+		proto, src, dst, h.Source.Port, h.Destination.Port))
+}
+
+func (h *Header) formatV2() []byte {
+	b := bytes.NewBuffer(make([]byte, 0, len(v2Signature)+4+v2MaxLength))
+	b.Write(v2Signature)
+	if h.Local {
+		b.Write([]byte{v2VersionLocal, v2FamilyUnspec, 0x00, 0x00})
+		return b.Bytes()
+	}
+
+	family := v2FamilyTCP4
+	src, dst := h.Source.IP.To4(), h.Destination.IP.To4()
+	if src == nil || dst == nil {
+		// Both addresses must be of the same family
+		family = v2FamilyTCP6
+		src, dst = h.Source.IP.To16(), h.Destination.IP.To16()
+	}
+	b.WriteByte(v2VersionProxy)
+	b.WriteByte(family)
+	_ = binary.Write(b, binary.BigEndian, uint16(2*len(src)+4))
+	b.Write(src)
+	b.Write(dst)
+	_ = binary.Write(b, binary.BigEndian, uint16(h.Source.Port))
+	_ = binary.Write(b, binary.BigEndian, uint16(h.Destination.Port))
+	return b.Bytes()
+}
+
+// Conn is a net.Conn whose addresses are
+// taken from a PROXY protocol header.
+type Conn struct {
+	net.Conn
+	r      *bufio.Reader
+	header *Header
+}
+
+// NewConn reads the PROXY protocol header from c and returns a Conn
+// reporting the original client address as RemoteAddr.
+// The read is aborted if the header was not received within timeout.
+func NewConn(c net.Conn, timeout time.Duration) (*Conn, error) {
+	if timeout > 0 {
+		if err := c.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+		defer func() { _ = c.SetReadDeadline(time.Time{}) }()
+	}
+	r := bufio.NewReader(c)
+	h, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{Conn: c, r: r, header: h}, nil
+}
+
+// Header returns the PROXY protocol header received on the connection.
+func (c *Conn) Header() *Header { return c.header }
+
+// Read reads data from the connection after the header.
+func (c *Conn) Read(b []byte) (int, error) { return c.r.Read(b) }
+
+// RemoteAddr returns the original client address.
+func (c *Conn) RemoteAddr() net.Addr {
+	if c.header.Local || c.header.Source == nil {
+		return c.Conn.RemoteAddr()
+	}
+	return c.header.Source
+}
+
+// LocalAddr returns the address the original client connected to.
+func (c *Conn) LocalAddr() net.Addr {
+	if c.header.Local || c.header.Destination == nil {
+		return c.Conn.LocalAddr()
+	}
+	return c.header.Destination
+}
@@ -0,0 +1,96 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead_v1(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nhello")))
+	h, err := Read(r)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), h.Version)
+	require.False(t, h.Local)
+	require.Equal(t, "192.168.0.1:56324", h.Source.String())
+	require.Equal(t, "192.168.0.11:443", h.Destination.String())
+	rest, _ := io.ReadAll(r)
+	require.Equal(t, "hello", string(rest))
+
+	h, err = Read(bufio.NewReader(bytes.NewReader([]byte("PROXY UNKNOWN ffff::1 ffff::2 1 2\r\n"))))
+	require.NoError(t, err)
+	require.True(t, h.Local)
+
+	_, err = Read(bufio.NewReader(bytes.NewReader([]byte("PROXY TCP4 192.168.0.1\r\n"))))
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestRead_v2(t *testing.T) {
+	b := append([]byte{}, v2Signature...)
+	b = append(b, v2VersionProxy, v2FamilyTCP4, 0x00, 12+3)
+	b = append(b, 10, 0, 0, 1, 10, 0, 0, 2, 0x1F, 0x90, 0x63, 0xDD)
+	b = append(b, 0x01, 0x02, 0x03) // some TLV bytes to skip
+	b = append(b, "hello"...)
+
+	r := bufio.NewReader(bytes.NewReader(b))
+	h, err := Read(r)
+	require.NoError(t, err)
+	require.Equal(t, byte(2), h.Version)
+	require.Equal(t, "10.0.0.1:8080", h.Source.String())
+	require.Equal(t, "10.0.0.2:25565", h.Destination.String())
+	rest, _ := io.ReadAll(r)
+	require.Equal(t, "hello", string(rest))
+
+	local := append(append([]byte{}, v2Signature...), v2VersionLocal, v2FamilyUnspec, 0x00, 0x00)
+	h, err = Read(bufio.NewReader(bytes.NewReader(local)))
+	require.NoError(t, err)
+	require.True(t, h.Local)
+}
+
+func TestRead_noHeader(t *testing.T) {
+	_, err := Read(bufio.NewReader(bytes.NewReader([]byte{0x10, 0x00, 0xF6, 0x05})))
+	require.ErrorIs(t, err, ErrNoHeader)
+}
+
+func TestNewConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() { _, _ = client.Write([]byte("PROXY TCP6 ::1 ::2 1234 25565\r\nhello")) }()
+
+	c, err := NewConn(server, 0)
+	require.NoError(t, err)
+	require.Equal(t, "[::1]:1234", c.RemoteAddr().String())
+	require.Equal(t, "[::2]:25565", c.LocalAddr().String())
+	b := make([]byte, 5)
+	_, err = io.ReadFull(c, b)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(b))
+}
+
+func TestHeader_Format(t *testing.T) {
+	src, dst := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 8080}, &net.TCPAddr{IP: net.ParseIP("::2"), Port: 25565}
+	for _, version := range []byte{1, 2} {
+		h := NewHeader(src, dst)
+		h.Version = version
+		b, err := h.Format()
+		require.NoError(t, err)
+
+		read, err := Read(bufio.NewReader(bytes.NewReader(b)))
+		require.NoError(t, err)
+		require.Equal(t, version, read.Version)
+		require.Equal(t, "10.0.0.1:8080", read.Source.String())
+		require.Equal(t, "[::2]:25565", read.Destination.String())
+	}
+
+	h := NewHeader(&net.UnixAddr{Name: "/tmp/sock"}, dst)
+	require.True(t, h.Local)
+	b, err := h.Format()
+	require.NoError(t, err)
+	read, err := Read(bufio.NewReader(bytes.NewReader(b)))
+	require.NoError(t, err)
+	require.True(t, read.Local)
+}
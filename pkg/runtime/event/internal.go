@@ -3,10 +3,18 @@ package event
 import (
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+var handlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "gate_event_handler_duration_seconds",
+	Help: "Duration of event subscriber handlers by event type.",
+}, []string{"event"})
+
 type manager struct {
 	activeSubscribers sync.WaitGroup // Wait for active subscribers to be done.
 	log               logr.Logger    // may be nil
@@ -112,9 +120,16 @@ func (m *manager) Fire(event Event) {
 	m.mu.RLock()
 	list := m.subscribers[eventType]
 	m.mu.RUnlock()
+	if len(list) == 0 {
+		return
+	}
 
+	duration := handlerDuration.WithLabelValues(eventType.String())
 	for _, sub := range list {
 		func() {
+			defer func(start time.Time) {
+				duration.Observe(time.Since(start).Seconds())
+			}(time.Now())
 			defer func() {
 				if r := recover(); r != nil {
 					m.log.Error(nil, "Recovered from panic from an event subscriber",